@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+var auditCmd = &cli.Command{
+	Name:  "audit",
+	Usage: "audit the github organization",
+	Subcommands: []*cli.Command{
+		auditAccessCmd,
+		auditSecurityCmd,
+	},
+}
+
+var auditAccessCmd = &cli.Command{
+	Name:  "access",
+	Usage: "list how users get access to repos and flag access not explained by teams.toml",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+			EnvVars: []string{
+				env.GithubRepos,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "output format: text or json",
+			Value: "text",
+		},
+		&cli.BoolFlag{
+			Name:  "unexplained",
+			Usage: "only print access not explained by teams.toml",
+		},
+		&cli.BoolFlag{
+			Name:  "remove-direct",
+			Usage: "remove unexplained direct grants and outside collaborators",
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("teams", "repos", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		if f := c.String("format"); f != "text" && f != "json" {
+			return fmt.Errorf("not supported format %s", f)
+		}
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+		sort.Strings(githubRepos)
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+
+		access, err := g.RepoAccess(ctx, githubRepos)
+		if err != nil {
+			return
+		}
+
+		unexplained := 0
+		for _, name := range githubRepos {
+			as := access[name]
+			// Repos not in repos.toml belong to no project.
+			model.ExplainAccess(repos[name], teams, as)
+
+			filtered := make([]model.Access, 0, len(as))
+			for _, a := range as {
+				if !a.Explained {
+					unexplained++
+				}
+				if a.Explained && c.Bool("unexplained") {
+					continue
+				}
+				filtered = append(filtered, a)
+			}
+			access[name] = filtered
+		}
+
+		if c.String("format") == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(access)
+			if err != nil {
+				return
+			}
+		} else {
+			for _, name := range githubRepos {
+				if len(access[name]) == 0 {
+					continue
+				}
+				fmt.Printf("%s:\n", name)
+				for _, a := range access[name] {
+					source := a.Source
+					if a.Team != "" {
+						source = fmt.Sprintf("%s %s", a.Source, a.Team)
+					}
+					status := ""
+					if !a.Explained {
+						status = "unexplained"
+					}
+					fmt.Printf("  %-20s %-10s %-30s %s\n", a.Login, a.Permission, source, status)
+				}
+			}
+		}
+
+		if c.Bool("remove-direct") {
+			for _, name := range githubRepos {
+				for _, a := range access[name] {
+					if a.Explained || (a.Source != model.AccessDirect && a.Source != model.AccessOutside) {
+						continue
+					}
+					err = g.RemoveCollaborator(ctx, name, a.Login)
+					if err != nil {
+						return
+					}
+					fmt.Fprintf(os.Stderr, "Removed %s access of %s from %s\n", a.Source, a.Login, name)
+				}
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Found %d unexplained access\n", unexplained)
+		return nil
+	},
+}
+
+var auditSecurityCmd = &cli.Command{
+	Name:  "security",
+	Usage: "check the security settings of repos",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "output format: text or json",
+			Value: "text",
+		},
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "fix failed checks which can be changed via API",
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		if f := c.String("format"); f != "text" && f != "json" {
+			return fmt.Errorf("not supported format %s", f)
+		}
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+		sort.Strings(githubRepos)
+
+		rs, err := g.AuditSecurity(ctx, githubRepos)
+		if err != nil {
+			return
+		}
+
+		if c.String("format") == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(rs)
+			if err != nil {
+				return
+			}
+		} else {
+			fmt.Print(model.FormatSecurityReports(rs))
+		}
+
+		if !c.Bool("fix") {
+			return nil
+		}
+		// A repo failed to fix doesn't stop fixing the others.
+		failed := 0
+		for _, r := range rs {
+			fixed, err := g.FixSecurity(ctx, r)
+			if len(fixed) > 0 {
+				fmt.Fprintf(os.Stderr, "Fixed %s: %s\n", r.Repo, strings.Join(fixed, ", "))
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to fix %s: %v\n", r.Repo, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to fix %d repos", failed)
+		}
+		return nil
+	},
+}
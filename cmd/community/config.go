@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+const configMetadataKey = "config"
+
+var configFlag = &cli.StringFlag{
+	Name:  "config",
+	Usage: "path to the community.toml",
+	Value: "community.toml",
+	EnvVars: []string{
+		env.Config,
+	},
+}
+
+var configCmd = &cli.Command{
+	Name:  "config",
+	Usage: "maintain community config",
+	Subcommands: []*cli.Command{
+		configValidateCmd,
+		configLintCmd,
+	},
+}
+
+var configValidateCmd = &cli.Command{
+	Name:  "validate",
+	Usage: "validate the community.toml and all files it references",
+	Action: func(c *cli.Context) (err error) {
+		cfg, err := model.LoadConfig(c.String("config"))
+		if err != nil {
+			return
+		}
+
+		err = cfg.Validate()
+		if err != nil {
+			return
+		}
+		fmt.Printf("Config %s is valid\n", c.String("config"))
+		return nil
+	},
+}
+
+var configLintCmd = &cli.Command{
+	Name:  "lint",
+	Usage: "lint teams.toml, repos.toml and users.toml",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+		},
+		&cli.StringFlag{
+			Name:  "users",
+			Usage: "path to the users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name, check repo patterns against github if set",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, repoFilterFlags()),
+	Before: withConfig(),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		var githubRepos []string
+		if c.String("owner") != "" && c.String("token") != "" {
+			g, err := services.NewGithub(
+				c.String("owner"),
+				c.String("token"))
+			if err != nil {
+				return err
+			}
+
+			githubRepos, err = g.ListRepos(ctx, getRepoFilter(c))
+			if err != nil {
+				return err
+			}
+		}
+
+		ps, err := model.Lint(model.Config{
+			Teams: c.String("teams"),
+			Repos: c.String("repos"),
+			Users: c.String("users"),
+		}, githubRepos)
+		if err != nil {
+			return
+		}
+
+		for _, p := range ps {
+			fmt.Println(p)
+		}
+		if len(ps) > 0 {
+			return fmt.Errorf("found %d problems", len(ps))
+		}
+		return nil
+	},
+}
+
+// loadConfig is the app level Before hook which loads community.toml.
+//
+// A missing config file is allowed unless the path is set explicitly.
+func loadConfig(c *cli.Context) (err error) {
+	path := c.String("config")
+
+	cfg := model.Config{}
+	_, err = os.Stat(path)
+	if err == nil || c.IsSet("config") {
+		cfg, err = model.LoadConfig(path)
+		if err != nil {
+			return
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	c.App.Metadata[configMetadataKey] = cfg
+	return nil
+}
+
+func getConfig(c *cli.Context) model.Config {
+	cfg, _ := c.App.Metadata[configMetadataKey].(model.Config)
+	return cfg
+}
+
+// withConfig returns a Before hook which fills flags that are not set by
+// command line or env with values from community.toml, and checks that all
+// required flags are present afterwards.
+func withConfig(required ...string) cli.BeforeFunc {
+	return func(c *cli.Context) (err error) {
+		defaults := configDefaults(getConfig(c), c.Command.FullName())
+
+		for _, f := range c.Command.Flags {
+			for _, name := range f.Names() {
+				v, ok := defaults[name]
+				if !ok || v == "" || c.IsSet(name) {
+					continue
+				}
+				err = c.Set(name, v)
+				if err != nil {
+					return fmt.Errorf("set flag %s from config: %w", name, err)
+				}
+			}
+		}
+
+		for _, name := range required {
+			if c.String(name) == "" {
+				return fmt.Errorf("required flag %q not set", name)
+			}
+		}
+		return nil
+	}
+}
+
+// configDefaults maps config values into flag values, command specific
+// defaults take precedence over sections.
+func configDefaults(cfg model.Config, command string) map[string]string {
+	m := map[string]string{
+		"owner":     cfg.Owner,
+		"teams":     cfg.Teams,
+		"repos":     cfg.Repos,
+		"users":     cfg.Users,
+		"labels":    cfg.Labels,
+		"actions":   cfg.Actions,
+		"secrets":   cfg.Secrets,
+		"snapshots": cfg.Snapshots,
+	}
+	repoFilterDefaults(cfg.Discovery, m)
+	matrixDefaults(cfg.Matrix, m)
+	unmanagedTeamDefaults(cfg.UnmanagedTeams, m)
+	if strings.HasPrefix(command, "report ") {
+		m["type"] = cfg.Report.Type
+		m["output"] = cfg.Report.Output
+	}
+	for k, v := range cfg.Defaults(command) {
+		m[k] = v
+	}
+	return m
+}
+
+// joinFlags joins groups of flags into one slice.
+func joinFlags(groups ...[]cli.Flag) []cli.Flag {
+	fs := make([]cli.Flag, 0)
+	for _, g := range groups {
+		fs = append(fs, g...)
+	}
+	return fs
+}
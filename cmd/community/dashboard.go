@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+var dashboardCmd = &cli.Command{
+	Name:  "dashboard",
+	Usage: "generate a static html dashboard of the health of all repos",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "directory to write the dashboard into",
+			Value: "dashboard",
+		},
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+			EnvVars: []string{
+				env.GithubRepos,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "actions",
+			Usage: "the folder of our actions, action sync status is skipped if not set",
+			EnvVars: []string{
+				env.GithubActions,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "snapshots",
+			Usage: "path to the report snapshots, used to render weekly trends",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("output", "teams", "repos", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+
+		// Repos without required actions are not synced at all.
+		sync := make(map[string]string)
+		if c.String("actions") != "" {
+			rs, err := g.SyncActions(ctx, c.String("actions"), repos, services.SyncActionsOptions{
+				DryRun: true,
+			})
+			if err != nil {
+				return err
+			}
+			for _, r := range rs {
+				if r.Unmanaged {
+					continue
+				}
+				sync[r.Repo] = model.ActionSyncInSync
+				if r.Diff != "" {
+					sync[r.Repo] = model.ActionSyncOutOfSync
+				}
+			}
+		}
+
+		d := model.Dashboard{
+			Owner:       c.String("owner"),
+			GeneratedAt: time.Now(),
+			Repos:       make([]model.RepoHealth, 0, len(repos)),
+		}
+		for _, repo := range repos {
+			h, err := g.RepoHealth(ctx, repo.Name)
+			if err != nil {
+				return err
+			}
+			h.Teams = teams.ProjectTeams(repo.Project)
+			if c.String("actions") != "" {
+				h.ActionSync = sync[repo.Name]
+				if h.ActionSync == "" {
+					h.ActionSync = model.ActionSyncUnmanaged
+				}
+			}
+			d.Repos = append(d.Repos, h)
+		}
+
+		if path := c.String("snapshots"); path != "" {
+			ss, err := model.LoadSnapshots(path)
+			if err != nil {
+				return err
+			}
+			d.Weeks = model.SnapshotWeeks(ss, c.String("owner"))
+		}
+
+		err = os.MkdirAll(c.String("output"), 0755)
+		if err != nil {
+			return
+		}
+		path := filepath.Join(c.String("output"), "index.html")
+		f, err := os.Create(path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+
+		err = model.RenderDashboard(f, d)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Generated dashboard %s\n", path)
+		return nil
+	},
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// repoFilterFlags returns flags that control which repos will be discovered.
+func repoFilterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "include-archived",
+			Usage: "include archived repos",
+		},
+		&cli.BoolFlag{
+			Name:  "include-private",
+			Usage: "include private and internal repos",
+		},
+		&cli.BoolFlag{
+			Name:  "exclude-forks",
+			Usage: "exclude forked repos",
+		},
+		&cli.BoolFlag{
+			Name:  "exclude-templates",
+			Usage: "exclude template repos",
+		},
+		&cli.StringSliceFlag{
+			Name:  "topic",
+			Usage: "only include repos with any of the topics",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-topic",
+			Usage: "exclude repos with any of the topics",
+		},
+	}
+}
+
+func getRepoFilter(c *cli.Context) model.RepoFilter {
+	return model.RepoFilter{
+		IncludeArchived:  c.Bool("include-archived"),
+		IncludePrivate:   c.Bool("include-private"),
+		ExcludeForks:     c.Bool("exclude-forks"),
+		ExcludeTemplates: c.Bool("exclude-templates"),
+		Topics:           c.StringSlice("topic"),
+		ExcludeTopics:    c.StringSlice("exclude-topic"),
+	}
+}
+
+// repoFilterDefaults maps the discovery section of config into flag values.
+func repoFilterDefaults(f model.RepoFilter, m map[string]string) {
+	bools := map[string]bool{
+		"include-archived":  f.IncludeArchived,
+		"include-private":   f.IncludePrivate,
+		"exclude-forks":     f.ExcludeForks,
+		"exclude-templates": f.ExcludeTemplates,
+	}
+	for k, v := range bools {
+		if v {
+			m[k] = "true"
+		}
+	}
+	if len(f.Topics) > 0 {
+		m["topic"] = strings.Join(f.Topics, ",")
+	}
+	if len(f.ExcludeTopics) > 0 {
+		m["exclude-topic"] = strings.Join(f.ExcludeTopics, ",")
+	}
+}
@@ -10,11 +10,22 @@ import (
 var app = &cli.App{
 	Name:  "community",
 	Usage: "community tools for open source society",
+	Flags: []cli.Flag{
+		configFlag,
+	},
+	Before: loadConfig,
 	Commands: []*cli.Command{
 		teamCmd,
 		reportCmd,
 		repoCmd,
 		trackCmd,
+		configCmd,
+		membersCmd,
+		usersCmd,
+		matrixCmd,
+		auditCmd,
+		dashboardCmd,
+		triageCmd,
 	},
 }
 
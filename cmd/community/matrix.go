@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+var matrixCmd = &cli.Command{
+	Name:  "matrix",
+	Usage: "maintain community matrix rooms",
+	Subcommands: []*cli.Command{
+		matrixSyncCmd,
+	},
+}
+
+var matrixSyncCmd = &cli.Command{
+	Name:  "sync",
+	Usage: "create public rooms for projects and invite their team members",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "users",
+			Usage: "path to the users.toml",
+			Value: "users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name, used as the prefix of room alias",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+	}, matrixFlags()),
+	Before: withConfig("teams", "users", "owner",
+		"matrix-home-server-url", "matrix-home-server", "matrix-user-id", "matrix-token"),
+	Action: func(c *cli.Context) (err error) {
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		users, err := model.LoadUsers(c.String("users"))
+		if err != nil {
+			return
+		}
+		if unknown := users.UnknownMembers(teams); len(unknown) > 0 {
+			return fmt.Errorf("members not in users registry: %s", strings.Join(unknown, ", "))
+		}
+
+		m, err := newMatrix(c)
+		if err != nil {
+			return
+		}
+
+		// Members of all projects.
+		projects := make(map[string]map[string]struct{})
+		for _, t := range teams {
+			if t.Project == "" {
+				continue
+			}
+			if projects[t.Project] == nil {
+				projects[t.Project] = make(map[string]struct{})
+			}
+			for _, v := range t.Members {
+				projects[t.Project][v] = struct{}{}
+			}
+		}
+
+		names := make([]string, 0, len(projects))
+		for name := range projects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, project := range names {
+			// Room alias looks like #beyondstorage@go-storage:matrix.org
+			alias := fmt.Sprintf("%s@%s", c.String("owner"), project)
+			roomid, err := m.GetRoom(alias)
+			if err != nil {
+				return err
+			}
+			if roomid == "" {
+				roomid, err = m.CreateRoom(alias)
+				if err != nil {
+					return err
+				}
+				err = m.PublicRoom(roomid)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Created room %s for project %s\n", alias, project)
+			}
+
+			joined, err := m.JoinedMembers(roomid)
+			if err != nil {
+				return err
+			}
+
+			logins := make([]string, 0, len(projects[project]))
+			for v := range projects[project] {
+				logins = append(logins, v)
+			}
+			sort.Strings(logins)
+			for _, login := range logins {
+				mid := users[login].Matrix
+				if mid == "" {
+					continue
+				}
+				if _, ok := joined[mid]; ok {
+					continue
+				}
+				err = m.Invite(roomid, mid)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Invited %s (%s) into room %s\n", users.DisplayName(login), mid, alias)
+			}
+		}
+		return nil
+	},
+}
+
+// matrixFlags returns flags to connect matrix.
+func matrixFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "matrix-home-server-url",
+			Usage: "matrix home server url",
+			EnvVars: []string{
+				env.MatrixHomeServerURL,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "matrix-home-server",
+			Usage: "matrix home server",
+			EnvVars: []string{
+				env.MatrixHomeServer,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "matrix-user-id",
+			Usage: "matrix user id",
+			EnvVars: []string{
+				env.MatrixUserId,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "matrix-token",
+			Usage: "matrix access token",
+			EnvVars: []string{
+				env.MatrixToken,
+			},
+		},
+	}
+}
+
+// matrixDefaults maps the matrix section of config into flag values.
+func matrixDefaults(cfg model.ConfigMatrix, m map[string]string) {
+	m["matrix-home-server-url"] = cfg.HomeServerURL
+	m["matrix-home-server"] = cfg.HomeServer
+	m["matrix-user-id"] = cfg.UserID
+}
+
+func newMatrix(c *cli.Context) (*services.Matrix, error) {
+	return services.NewMatrix(
+		c.String("matrix-home-server-url"),
+		c.String("matrix-home-server"),
+		c.String("matrix-user-id"),
+		c.String("matrix-token"))
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+var membersCmd = &cli.Command{
+	Name:  "members",
+	Usage: "maintain community members",
+	Subcommands: []*cli.Command{
+		membersSuggestCmd,
+		membersInactiveCmd,
+	},
+}
+
+var membersSuggestCmd = &cli.Command{
+	Name:  "suggest",
+	Usage: "suggest role promotions based on contribution history",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+		},
+		&cli.StringFlag{
+			Name:  "window",
+			Usage: "only count contributions in the window, like 90d or 6mo",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("teams", "repos", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		policy := getConfig(c).Promotion
+		if c.String("window") != "" {
+			d, err := model.ParseDuration(c.String("window"))
+			if err != nil {
+				return err
+			}
+			policy.Window = model.Duration(d)
+		}
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+
+		since := policy.GetWindow().Since(time.Now())
+		repoActivities := make(map[string]model.Activities)
+		activities := make(map[string]model.Activities)
+		for project, names := range repos.ParsedProjects() {
+			as := make(model.Activities)
+			for _, name := range names {
+				ra, ok := repoActivities[name]
+				if !ok {
+					ra, err = g.RepoActivities(ctx, name, since)
+					if err != nil {
+						return
+					}
+					repoActivities[name] = ra
+				}
+				as.Merge(ra)
+			}
+			activities[project] = as
+		}
+
+		ps := model.SuggestPromotions(teams, activities, policy)
+		if len(ps) == 0 {
+			fmt.Println("No promotions to suggest")
+			return nil
+		}
+
+		fmt.Printf("## Suggested promotions\n\n")
+		for _, p := range ps {
+			fmt.Printf("- %s\n", p)
+			printEvidence("merged pull requests", p.Activity.MergedPRs)
+			printEvidence("reviews", p.Activity.Reviews)
+			printEvidence("triaged issues", p.Activity.Triages)
+		}
+
+		patch, err := model.PromotionPatch(teams, ps)
+		if err != nil {
+			return
+		}
+		fmt.Printf("\n## Patch for teams.toml\n\n```toml\n%s```\n", patch)
+		return nil
+	},
+}
+
+var membersInactiveCmd = &cli.Command{
+	Name:  "inactive",
+	Usage: "detect inactive team members and move them to emeritus",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "members without activity since then are inactive, like 90d or 12mo",
+			Value: "12mo",
+		},
+		&cli.BoolFlag{
+			Name:  "pr",
+			Usage: "create a pull request to move inactive members to emeritus",
+		},
+		&cli.StringFlag{
+			Name:  "config-repo",
+			Usage: "the repo which stores teams.toml, required by --pr",
+		},
+		&cli.StringFlag{
+			Name:  "config-path",
+			Usage: "path to the teams.toml in config repo",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("teams", "repos", "since", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		if c.Bool("pr") && c.String("config-repo") == "" {
+			return fmt.Errorf("--config-repo is required by --pr")
+		}
+
+		d, err := model.ParseDuration(c.String("since"))
+		if err != nil {
+			return
+		}
+		since := time.Now().Add(-d)
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+		projects := repos.ParsedProjects()
+
+		names := make([]string, 0, len(teams))
+		for name := range teams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// Cache last activity by login and project.
+		cache := make(map[string]model.LastActivity)
+		moves := make(map[string][]string)
+		b := &strings.Builder{}
+		b.WriteString("| Team | Member | Last activity |\n| ---- | ---- | ---- |\n")
+		for _, name := range names {
+			t := teams[name]
+			teamRepos := githubRepos
+			if t.Project != "" {
+				teamRepos = projects[t.Project]
+			}
+
+			for _, m := range t.Members {
+				key := m + "/" + t.Project
+				last, ok := cache[key]
+				if !ok {
+					last, err = g.LastActivity(ctx, m, teamRepos)
+					if err != nil {
+						return
+					}
+					cache[key] = last
+				}
+				if last.At.After(since) {
+					continue
+				}
+
+				moves[name] = append(moves[name], m)
+				activity := "never"
+				if !last.At.IsZero() {
+					activity = fmt.Sprintf("[%s](%s) at %s", last.Kind, last.URL, last.At.Format("2006-01-02"))
+				}
+				b.WriteString(fmt.Sprintf("| %s | @%s | %s |\n", name, m, activity))
+			}
+		}
+
+		if len(moves) == 0 {
+			fmt.Printf("No inactive members since %s\n", since.Format("2006-01-02"))
+			return nil
+		}
+		fmt.Print(b.String())
+
+		if !c.Bool("pr") {
+			return nil
+		}
+
+		content, sha, err := g.GetFile(ctx, c.String("config-repo"), c.String("config-path"))
+		if err != nil {
+			return
+		}
+		if content == nil {
+			return fmt.Errorf("%s is not found in %s", c.String("config-path"), c.String("config-repo"))
+		}
+
+		content, err = model.MoveToEmeritus(content, moves)
+		if err != nil {
+			return
+		}
+
+		url, err := g.ProposeChanges(ctx, c.String("config-repo"),
+			"teams: Move inactive members to emeritus",
+			fmt.Sprintf("Following members have no activity since %s:\n\n%s", since.Format("2006-01-02"), b.String()),
+			[]services.FileChange{{Path: c.String("config-path"), Content: content, SHA: sha}})
+		if err != nil {
+			return
+		}
+		fmt.Printf("Create pull request %s\n", url)
+		return nil
+	},
+}
+
+func printEvidence(name string, links []string) {
+	if len(links) == 0 {
+		return
+	}
+	fmt.Printf("  - %s (%d)\n", name, len(links))
+	for _, v := range links {
+		fmt.Printf("    - %s\n", v)
+	}
+}
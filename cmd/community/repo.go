@@ -1,12 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"github.com/beyondstorage/go-community/model"
-	"github.com/beyondstorage/go-community/services"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
 	"github.com/urfave/cli/v2"
 
 	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
 )
 
 var repoCmd = &cli.Command{
@@ -14,45 +23,55 @@ var repoCmd = &cli.Command{
 	Usage: "maintain community repos",
 	Subcommands: []*cli.Command{
 		repoSyncActionsCmd,
+		repoSyncOwnersCmd,
+		repoSyncSecretsCmd,
+		repoSealSecretsCmd,
+		repoBumpPinsCmd,
+		repoExplainCmd,
 	},
 }
 
 var repoSyncActionsCmd = &cli.Command{
 	Name: "sync-actions",
-	Flags: []cli.Flag{
+	Flags: joinFlags([]cli.Flag{
 		&cli.StringFlag{
-			Name:     "owner",
-			Usage:    "github organization name",
-			Required: true,
+			Name:  "owner",
+			Usage: "github organization name",
 			EnvVars: []string{
 				env.GithubOwner,
 			},
 		},
 		&cli.StringFlag{
-			Name:     "token",
-			Usage:    "github access token",
-			Required: true,
+			Name:  "token",
+			Usage: "github access token",
 			EnvVars: []string{
 				env.GithubAccessToken,
 			},
 		},
 		&cli.StringFlag{
-			Name:     "actions",
-			Usage:    "the folder of our actions",
-			Required: true,
+			Name:  "actions",
+			Usage: "the folder of our actions",
 			EnvVars: []string{
 				env.GithubActions,
 			},
 		},
 		&cli.StringFlag{
-			Name:     "repos",
-			Usage:    "the folder of our repos",
-			Required: true,
+			Name:  "repos",
+			Usage: "path to the repos.toml",
 			EnvVars: []string{
 				env.GithubRepos,
 			},
 		},
-	},
+		&cli.BoolFlag{
+			Name:  "pin",
+			Usage: "pin actions used in workflows to commit shas",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only print the diff of workflows out of sync without opening pull requests",
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("owner", "token", "actions", "repos"),
 	Action: func(c *cli.Context) (err error) {
 		ctx := context.Background()
 
@@ -63,7 +82,7 @@ var repoSyncActionsCmd = &cli.Command{
 			return
 		}
 
-		githubRepos, err := g.ListRepos(ctx)
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
 		if err != nil {
 			return
 		}
@@ -73,11 +92,412 @@ var repoSyncActionsCmd = &cli.Command{
 			return err
 		}
 
-		err = g.SyncActions(ctx, c.String("actions"), repos)
+		rs, err := g.SyncActions(ctx, c.String("actions"), repos, services.SyncActionsOptions{
+			Pin:    c.Bool("pin"),
+			DryRun: c.Bool("dry-run"),
+		})
+		if err != nil {
+			return
+		}
+
+		sort.Slice(rs, func(i, j int) bool {
+			return rs[i].Repo < rs[j].Repo
+		})
+		findings := 0
+		for _, r := range rs {
+			if r.PullRequest != "" {
+				fmt.Printf("%s: %s\n", r.Repo, r.PullRequest)
+			}
+			if c.Bool("dry-run") && r.Diff != "" {
+				fmt.Printf("%s: out of sync\n%s\n", r.Repo, r.Diff)
+			}
+			for _, f := range r.Findings {
+				fmt.Printf("%s: %s\n", r.Repo, f)
+			}
+			findings += len(r.Findings)
+		}
+		if findings > 0 {
+			fmt.Printf("Found %d risky patterns in workflows\n", findings)
+		}
+		return nil
+	},
+}
+
+var repoBumpPinsCmd = &cli.Command{
+	Name:  "bump-pins",
+	Usage: "bump actions in local workflows to their latest tags",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "actions",
+			Usage: "the folder of our actions",
+			EnvVars: []string{
+				env.GithubActions,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "major",
+			Usage: "allow bumping to a new major version",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only print workflows to be bumped",
+		},
+	},
+	Before: withConfig("token", "actions"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		// Actions are resolved from their own repos, owner is not used.
+		g, err := services.NewGithub("", c.String("token"))
+		if err != nil {
+			return
+		}
+
+		paths := make([]string, 0)
+		for _, pattern := range []string{"*.yml", "*.yaml"} {
+			ps, err := filepath.Glob(filepath.Join(c.String("actions"), pattern))
+			if err != nil {
+				return err
+			}
+			paths = append(paths, ps...)
+		}
+
+		pinner := g.NewActionPinner()
+		for _, path := range paths {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			bumped, err := pinner.Bump(ctx, data, c.Bool("major"))
+			if err != nil {
+				return fmt.Errorf("bump %s: %w", path, err)
+			}
+			if bytes.Equal(data, bumped) {
+				continue
+			}
+
+			fmt.Printf("Bumped %s\n", path)
+			if c.Bool("dry-run") {
+				continue
+			}
+			err = ioutil.WriteFile(path, bumped, 0644)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var repoSyncOwnersCmd = &cli.Command{
+	Name:  "sync-owners",
+	Usage: "sync MAINTAINERS.md and CODEOWNERS of repos with teams.toml",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+			EnvVars: []string{
+				env.GithubRepos,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "users",
+			Usage: "path to the users.toml, used to render display names",
+			Value: "users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only print repos out of sync without opening pull requests",
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("teams", "repos", "users", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		users, err := loadUsersIfExist(c)
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+
+		changed, err := g.SyncOwners(ctx, teams, repos, users, c.Bool("dry-run"))
+		if err != nil {
+			return
+		}
+
+		names := make([]string, 0, len(changed))
+		for name := range changed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s: %s\n", name, strings.Join(changed[name], ", "))
+		}
+		return nil
+	},
+}
+
+var repoSyncSecretsCmd = &cli.Command{
+	Name:  "sync-secrets",
+	Usage: "sync actions secrets and variables of repos with repos.toml",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			EnvVars: []string{
+				env.GithubRepos,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "secrets",
+			Usage: "path to the encrypted secrets file",
+		},
+		&cli.StringFlag{
+			Name:  "key",
+			Usage: "base64 encoded key of the secrets file",
+			EnvVars: []string{
+				env.SecretsKey,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only print secrets and variables to be changed",
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("owner", "token", "repos"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		values, err := loadSecretValues(c)
+		if err != nil {
+			return
+		}
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
 		if err != nil {
 			return
 		}
 
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+
+		// Check all values before touching any repo.
+		secrets, variables := values.Missing(repos)
+		if len(secrets) > 0 || len(variables) > 0 {
+			return fmt.Errorf("missing values of secrets [%s] and variables [%s]",
+				strings.Join(secrets, ", "), strings.Join(variables, ", "))
+		}
+
+		rs, err := g.SyncSecrets(ctx, repos, values, c.Bool("dry-run"))
+		if err != nil {
+			return
+		}
+
+		sort.Slice(rs, func(i, j int) bool {
+			return rs[i].Repo < rs[j].Repo
+		})
+		for _, r := range rs {
+			if len(r.Created) > 0 {
+				fmt.Printf("%s: created %s\n", r.Repo, strings.Join(r.Created, ", "))
+			}
+			if len(r.Updated) > 0 {
+				fmt.Printf("%s: updated %s\n", r.Repo, strings.Join(r.Updated, ", "))
+			}
+			if len(r.Extra) > 0 {
+				fmt.Printf("%s: not in repos.toml %s\n", r.Repo, strings.Join(r.Extra, ", "))
+			}
+		}
 		return nil
 	},
 }
+
+var repoSealSecretsCmd = &cli.Command{
+	Name:      "seal-secrets",
+	Usage:     "encrypt a plain toml of secret and variable values into the secrets file",
+	ArgsUsage: "<plain.toml>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "secrets",
+			Usage: "path to the encrypted secrets file",
+		},
+		&cli.StringFlag{
+			Name:  "key",
+			Usage: "base64 encoded key of the secrets file",
+			EnvVars: []string{
+				env.SecretsKey,
+			},
+		},
+	},
+	Before: withConfig("secrets", "key"),
+	Action: func(c *cli.Context) (err error) {
+		path := c.Args().First()
+		if path == "" {
+			return fmt.Errorf("plain toml is required")
+		}
+
+		key, err := model.ParseSecretKey(c.String("key"))
+		if err != nil {
+			return
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return
+		}
+
+		sealed, err := model.EncryptSecretValues(data, key)
+		if err != nil {
+			return
+		}
+		return ioutil.WriteFile(c.String("secrets"), sealed, 0644)
+	},
+}
+
+// loadSecretValues loads values from the encrypted secrets file if given,
+// values from env like COMMUNITY_SECRET_<NAME> take precedence.
+func loadSecretValues(c *cli.Context) (values model.SecretValues, err error) {
+	if path := c.String("secrets"); path != "" {
+		key, err := model.ParseSecretKey(c.String("key"))
+		if err != nil {
+			return values, err
+		}
+		values, err = model.LoadSecretValues(path, key)
+		if err != nil {
+			return values, err
+		}
+	}
+	if values.Secrets == nil {
+		values.Secrets = make(map[string]string)
+	}
+	if values.Variables == nil {
+		values.Variables = make(map[string]string)
+	}
+
+	for _, kv := range os.Environ() {
+		s := strings.SplitN(kv, "=", 2)
+		switch {
+		case strings.HasPrefix(s[0], env.SecretPrefix):
+			values.Secrets[strings.TrimPrefix(s[0], env.SecretPrefix)] = s[1]
+		case strings.HasPrefix(s[0], env.VariablePrefix):
+			values.Variables[strings.TrimPrefix(s[0], env.VariablePrefix)] = s[1]
+		}
+	}
+	return values, nil
+}
+
+var repoExplainCmd = &cli.Command{
+	Name:      "explain",
+	Usage:     "explain how the repo config is resolved from repos.toml",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+			EnvVars: []string{
+				env.GithubRepos,
+			},
+		},
+	},
+	Before: withConfig("repos"),
+	Action: func(c *cli.Context) (err error) {
+		name := c.Args().First()
+		if name == "" {
+			return fmt.Errorf("repo name is required")
+		}
+
+		ms, repo, err := model.ExplainRepo(c.String("repos"), name)
+		if err != nil {
+			return
+		}
+
+		fmt.Printf("Repo %s matched %d patterns:\n", name, len(ms))
+		inherited := true
+		for i, m := range ms {
+			status := ""
+			switch {
+			case i == 0:
+				status = " (winner)"
+			case inherited:
+				status = " (inherited)"
+			}
+			inherited = inherited && m.Repo.Inherit
+			fmt.Printf("  %d. %q%s\n", i+1, m.Pattern, status)
+		}
+
+		fmt.Printf("\nResolved config:\n\n")
+		return toml.NewEncoder(os.Stdout).Encode(model.Repos{name: repo})
+	},
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
@@ -20,40 +21,54 @@ var reportCmd = &cli.Command{
 	Usage: "maintain community reports",
 	Subcommands: []*cli.Command{
 		reportWeeklyCmd,
+		reportDiversityCmd,
+		reportTrendCmd,
+		reportAttentionCmd,
 	},
 }
 
 var reportWeeklyCmd = &cli.Command{
 	Name: "weekly",
-	Flags: []cli.Flag{
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "type of report",
+			Value: "issue",
+		},
 		&cli.StringFlag{
-			Name:     "type",
-			Usage:    "type of report",
-			Required: true,
-			Value:    "issue",
+			Name:  "output",
+			Usage: "destination of report",
 		},
 		&cli.StringFlag{
-			Name:     "output",
-			Usage:    "destination of report",
-			Required: true,
+			Name:  "users",
+			Usage: "path to the users.toml, used to render display names",
+			Value: "users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "snapshots",
+			Usage: "path to the report snapshots, statistics are appended and trends are added into the report",
+		},
+		&cli.IntFlag{
+			Name:  "weeks",
+			Usage: "number of weeks in trends",
+			Value: 8,
 		},
 		&cli.StringFlag{
-			Name:     "owner",
-			Usage:    "github organization name",
-			Required: true,
+			Name:  "owner",
+			Usage: "github organization name",
 			EnvVars: []string{
 				env.GithubOwner,
 			},
 		},
 		&cli.StringFlag{
-			Name:     "token",
-			Usage:    "github access token",
-			Required: true,
+			Name:  "token",
+			Usage: "github access token",
 			EnvVars: []string{
 				env.GithubAccessToken,
 			},
 		},
-	},
+	}, repoFilterFlags()),
+	Before: withConfig("type", "output", "owner", "token"),
 	Action: func(c *cli.Context) error {
 		logger, _ := zap.NewDevelopment()
 
@@ -71,7 +86,12 @@ var reportWeeklyCmd = &cli.Command{
 
 		ctx := context.Background()
 
-		repos, err := g.ListRepos(ctx)
+		registry, err := loadUsersIfExist(c)
+		if err != nil {
+			return err
+		}
+
+		repos, err := g.ListRepos(ctx, getRepoFilter(c))
 		if err != nil {
 			return err
 		}
@@ -81,13 +101,23 @@ var reportWeeklyCmd = &cli.Command{
 
 		usernameDict := make(map[string]bool)
 		statistics := make([]model.Statistic, 0, len(repos))
+		now := time.Now()
+		snapshots := make([]model.Snapshot, 0, len(repos))
 
 		for _, v := range repos {
-			content, users, stat, err := g.GenerateReportDataByRepo(ctx, c.String("owner"), v)
+			content, users, stat, err := g.GenerateReportDataByRepo(ctx, c.String("owner"), v, registry)
 			if err != nil {
 				return nil
 			}
 
+			// blank statistic is stored as well, so that trends drop to zero.
+			snapshots = append(snapshots, model.Snapshot{
+				Time:      now,
+				Owner:     c.String("owner"),
+				Repo:      v,
+				Statistic: stat,
+			})
+
 			// skip generate content for repo whose statistic is blank
 			if stat.IsBlank() {
 				continue
@@ -116,6 +146,14 @@ var reportWeeklyCmd = &cli.Command{
 		// print statistics before report content
 		result := fmt.Sprintf("%s\n%s\n", resStat.FormatPrint(), b.String())
 
+		if path := c.String("snapshots"); path != "" {
+			trend, err := appendSnapshots(path, c.String("owner"), snapshots, c.Int("weeks"))
+			if err != nil {
+				return err
+			}
+			result = fmt.Sprintf("%s%s\n%s\n", resStat.FormatPrint(), trend, b.String())
+		}
+
 		url, err := g.CreateWeeklyReportIssue(ctx, c.String("output"), result)
 		if err != nil {
 			return err
@@ -124,3 +162,298 @@ var reportWeeklyCmd = &cli.Command{
 		return nil
 	},
 }
+
+var reportTrendCmd = &cli.Command{
+	Name:  "trend",
+	Usage: "print week-over-week trends from report snapshots",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "snapshots",
+			Usage: "path to the report snapshots",
+		},
+		&cli.IntFlag{
+			Name:  "weeks",
+			Usage: "number of weeks in trends",
+			Value: 8,
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+	},
+	Before: withConfig("snapshots", "owner"),
+	Action: func(c *cli.Context) error {
+		ss, err := model.LoadSnapshots(c.String("snapshots"))
+		if err != nil {
+			return err
+		}
+		fmt.Print(model.FormatTrend(lastWeeks(model.SnapshotWeeks(ss, c.String("owner")), c.Int("weeks"))))
+		return nil
+	},
+}
+
+// appendSnapshots stores snapshots of this run and returns trends of the
+// owner in the last weeks including this run.
+func appendSnapshots(path, owner string, snapshots []model.Snapshot, weeks int) (string, error) {
+	err := model.AppendSnapshots(path, snapshots)
+	if err != nil {
+		return "", err
+	}
+	ss, err := model.LoadSnapshots(path)
+	if err != nil {
+		return "", err
+	}
+	return model.FormatTrend(lastWeeks(model.SnapshotWeeks(ss, owner), weeks)), nil
+}
+
+func lastWeeks(weeks []model.WeeklyStatistic, n int) []model.WeeklyStatistic {
+	if n > 0 && len(weeks) > n {
+		return weeks[len(weeks)-n:]
+	}
+	return weeks
+}
+
+var reportDiversityCmd = &cli.Command{
+	Name:  "diversity",
+	Usage: "report contributions and maintainers by affiliation",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "type of report",
+			Value: "issue",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "destination of report",
+		},
+		&cli.StringFlag{
+			Name:  "window",
+			Usage: "only count contributions in the window, like 90d or 6mo",
+			Value: "90d",
+		},
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+		},
+		&cli.StringFlag{
+			Name:  "users",
+			Usage: "path to the users.toml",
+			Value: "users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("type", "output", "window", "teams", "repos", "users", "owner", "token"),
+	Action: func(c *cli.Context) error {
+		if c.String("type") != "issue" {
+			return errors.New("not supported type")
+		}
+
+		d, err := model.ParseDuration(c.String("window"))
+		if err != nil {
+			return err
+		}
+		since := time.Now().Add(-d)
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return err
+		}
+
+		users, err := model.LoadUsers(c.String("users"))
+		if err != nil {
+			return err
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return err
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return err
+		}
+		projects := repos.ParsedProjects()
+
+		names := make([]string, 0, len(projects))
+		for name := range projects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b := &strings.Builder{}
+		repoActivities := make(map[string]model.Activities)
+		for _, project := range names {
+			as := make(model.Activities)
+			for _, name := range projects[project] {
+				ra, ok := repoActivities[name]
+				if !ok {
+					ra, err = g.RepoActivities(ctx, name, since)
+					if err != nil {
+						return err
+					}
+					repoActivities[name] = ra
+				}
+				as.Merge(ra)
+			}
+
+			b.WriteString(model.NewDiversity(project, as, teams, users).FormatPrint())
+		}
+
+		url, err := g.CreateIssue(ctx, c.String("output"),
+			fmt.Sprintf("Diversity report since %s", since.Format("2006-01-02")), b.String())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Create issue %s\n", url)
+		return nil
+	},
+}
+
+var reportAttentionCmd = &cli.Command{
+	Name:  "attention",
+	Usage: "report issues without maintainer response and pull requests awaiting review by project",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "type of report",
+			Value: "issue",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "destination of report",
+		},
+		&cli.StringFlag{
+			Name:  "within",
+			Usage: "items should be responded within the duration, like 3d or 1w",
+			Value: "3d",
+		},
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("type", "output", "within", "teams", "repos", "owner", "token"),
+	Action: func(c *cli.Context) error {
+		if c.String("type") != "issue" {
+			return errors.New("not supported type")
+		}
+
+		d, err := model.ParseDuration(c.String("within"))
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		since := now.Add(-d)
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return err
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return err
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(repos))
+		for name := range repos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		items := make([]model.AttentionItem, 0)
+		for _, name := range names {
+			projects := repos[name].Project
+			is, err := g.AttentionItems(ctx, name, since, func(login string) bool {
+				return teams.IsResponder(projects, login)
+			})
+			if err != nil {
+				return err
+			}
+			items = append(items, is...)
+		}
+
+		b := &strings.Builder{}
+		as := model.NewAttentions(repos, teams, items)
+		if len(as) == 0 {
+			b.WriteString("Nothing needs attention.\n")
+		}
+		for _, a := range as {
+			b.WriteString(a.FormatPrint(c.String("owner"), now))
+		}
+
+		url, err := g.CreateIssue(ctx, c.String("output"),
+			fmt.Sprintf("Attention report: no response within %s", c.String("within")), b.String())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Create issue %s\n", url)
+		return nil
+	},
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
@@ -15,41 +17,44 @@ var teamCmd = &cli.Command{
 	Usage: "maintain community teams",
 	Subcommands: []*cli.Command{
 		teamSyncCmd,
+		teamUnmanagedCmd,
 	},
 }
 
 var teamSyncCmd = &cli.Command{
 	Name: "sync",
-	Flags: []cli.Flag{
+	Flags: joinFlags([]cli.Flag{
 		&cli.StringFlag{
-			Name:     "teams",
-			Usage:    "path to the teams.toml",
-			Required: true,
-			Value:    "teams.toml",
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
 		},
 		&cli.StringFlag{
-			Name:     "repos",
-			Usage:    "path to the repos.toml",
-			Required: true,
-			Value:    "repos.toml",
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
 		},
 		&cli.StringFlag{
-			Name:     "owner",
-			Usage:    "github organization name",
-			Required: true,
+			Name:  "users",
+			Usage: "path to the users.toml",
+			Value: "users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
 			EnvVars: []string{
 				env.GithubOwner,
 			},
 		},
 		&cli.StringFlag{
-			Name:     "token",
-			Usage:    "github access token",
-			Required: true,
+			Name:  "token",
+			Usage: "github access token",
 			EnvVars: []string{
 				env.GithubAccessToken,
 			},
 		},
-	},
+	}, repoFilterFlags(), matrixFlags(), unmanagedTeamFlags()),
+	Before: withConfig("teams", "repos", "users", "owner", "token"),
 	Action: func(c *cli.Context) (err error) {
 		ctx := context.Background()
 
@@ -65,7 +70,16 @@ var teamSyncCmd = &cli.Command{
 			return
 		}
 
-		githubRepos, err := g.ListRepos(ctx)
+		// Users registry is the source of truth of members.
+		users, err := model.LoadUsers(c.String("users"))
+		if err != nil {
+			return
+		}
+		if unknown := users.UnknownMembers(team); len(unknown) > 0 {
+			return fmt.Errorf("members not in users registry: %s", strings.Join(unknown, ", "))
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
 		if err != nil {
 			return
 		}
@@ -80,10 +94,181 @@ var teamSyncCmd = &cli.Command{
 			return
 		}
 
-		err = g.SyncContributors(ctx, team, githubRepos)
+		err = handleUnmanagedTeams(ctx, g, team, getUnmanagedTeamPolicy(c))
+		if err != nil {
+			return
+		}
+
+		policy := getConfig(c).Contributors
+		invited, err := g.SyncContributors(ctx, team, githubRepos, policy)
 		if err != nil {
 			return
 		}
+
+		if len(invited) > 0 && policy.Welcome.Enabled() {
+			err = welcomeContributors(ctx, c, g, users, invited, policy.Welcome)
+			if err != nil {
+				return
+			}
+		}
 		return nil
 	},
 }
+
+var teamUnmanagedCmd = &cli.Command{
+	Name:  "unmanaged",
+	Usage: "report or prune teams which exist on github but not in teams.toml",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "teams",
+			Usage: "path to the teams.toml",
+			Value: "teams.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	}, unmanagedTeamFlags()),
+	Before: withConfig("teams", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		teams, err := model.LoadTeams(c.String("teams"))
+		if err != nil {
+			return
+		}
+
+		return handleUnmanagedTeams(ctx, g, teams, getUnmanagedTeamPolicy(c))
+	},
+}
+
+// unmanagedTeamFlags returns flags that control how to handle teams not in
+// teams.toml.
+func unmanagedTeamFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "unmanaged",
+			Usage: "how to handle teams not in teams.toml: report, revoke or delete",
+			Value: model.UnmanagedReport,
+		},
+		&cli.StringSliceFlag{
+			Name:  "allow-team",
+			Usage: "glob patterns of teams not in teams.toml to keep",
+		},
+	}
+}
+
+// getUnmanagedTeamPolicy builds the policy from flags, the team contributors
+// are invited into is managed by the contributors policy and always kept.
+func getUnmanagedTeamPolicy(c *cli.Context) model.UnmanagedTeamPolicy {
+	p := model.UnmanagedTeamPolicy{
+		Mode:  c.String("unmanaged"),
+		Allow: c.StringSlice("allow-team"),
+	}
+	if t := getConfig(c).Contributors.Team; t != "" {
+		p.Allow = append(p.Allow, t)
+	}
+	return p
+}
+
+// unmanagedTeamDefaults maps the unmanaged_teams section of config into
+// flag values.
+func unmanagedTeamDefaults(p model.UnmanagedTeamPolicy, m map[string]string) {
+	m["unmanaged"] = p.Mode
+	if len(p.Allow) > 0 {
+		m["allow-team"] = strings.Join(p.Allow, ",")
+	}
+}
+
+// handleUnmanagedTeams prints all unmanaged teams and prunes them following
+// the policy.
+func handleUnmanagedTeams(ctx context.Context, g *services.Github, teams model.Teams, policy model.UnmanagedTeamPolicy) (err error) {
+	if err = policy.Validate(); err != nil {
+		return
+	}
+
+	ts, err := g.UnmanagedTeams(ctx, teams, policy)
+	if err != nil {
+		return
+	}
+
+	for _, t := range ts {
+		action := policy.GetMode()
+		switch {
+		case t.Allowed:
+			action = "allowed"
+		case action == model.UnmanagedDelete && len(t.Children) > 0:
+			action = "kept, has child teams " + strings.Join(t.Children, ", ")
+		}
+		fmt.Printf("Unmanaged team %s (%s): %d members, repos: %s\n",
+			t.Slug, action, t.Members, strings.Join(t.Repos, ", "))
+
+		err = g.PruneTeam(ctx, t, policy.GetMode())
+		if err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// welcomeContributors sends welcome messages to newly invited contributors.
+func welcomeContributors(ctx context.Context, c *cli.Context, g *services.Github, users model.Users, invited []model.Contributor, policy model.WelcomePolicy) (err error) {
+	tmpl, err := policy.LoadTemplate()
+	if err != nil {
+		return
+	}
+
+	var m *services.Matrix
+	if policy.Matrix {
+		m, err = newMatrix(c)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, v := range invited {
+		repo, number, url, err := g.LatestMergedPR(ctx, v.Login)
+		if err != nil {
+			return err
+		}
+
+		content, err := model.NewWelcome(v.Login, c.String("owner"), policy.DocsURL, url).Render(tmpl)
+		if err != nil {
+			return err
+		}
+
+		if policy.Comment && repo != "" {
+			commentURL, err := g.CreateComment(ctx, repo, number, content)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Welcomed %s in %s\n", v.Login, commentURL)
+		}
+
+		if policy.Matrix && users[v.Login].Matrix != "" {
+			err = m.SendDirectMessage(users[v.Login].Matrix, content)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Welcomed %s via matrix %s\n", v.Login, users[v.Login].Matrix)
+		}
+	}
+	return nil
+}
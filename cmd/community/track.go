@@ -15,7 +15,7 @@ import (
 var trackCmd = &cli.Command{
 	Name:  "track",
 	Usage: "maintain community tracking issues",
-	Flags: []cli.Flag{
+	Flags: joinFlags([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "title",
 			Usage: "the tracking issue title",
@@ -29,22 +29,21 @@ var trackCmd = &cli.Command{
 			Usage: "the tracking repos, support glob style like go-service-*",
 		},
 		&cli.StringFlag{
-			Name:     "owner",
-			Usage:    "github organization name",
-			Required: true,
+			Name:  "owner",
+			Usage: "github organization name",
 			EnvVars: []string{
 				env.GithubOwner,
 			},
 		},
 		&cli.StringFlag{
-			Name:     "token",
-			Usage:    "github access token",
-			Required: true,
+			Name:  "token",
+			Usage: "github access token",
 			EnvVars: []string{
 				env.GithubAccessToken,
 			},
 		},
-	},
+	}, repoFilterFlags()),
+	Before: withConfig("owner", "token"),
 	Action: func(c *cli.Context) (err error) {
 		owner := c.String("owner")
 
@@ -67,7 +66,7 @@ var trackCmd = &cli.Command{
 			return err
 		}
 
-		repos, err := g.ListRepos(ctx)
+		repos, err := g.ListRepos(ctx, getRepoFilter(c))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+var triageCmd = &cli.Command{
+	Name:  "triage",
+	Usage: "triage issues and pull requests",
+	Subcommands: []*cli.Command{
+		triageStaleCmd,
+	},
+}
+
+var triageStaleCmd = &cli.Command{
+	Name:  "stale",
+	Usage: "mark, unmark and close stale issues and pull requests by the stale policy",
+	Flags: joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "repos",
+			Usage: "path to the repos.toml",
+			Value: "repos.toml",
+			EnvVars: []string{
+				env.GithubRepos,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only print actions to take",
+		},
+	}, repoFilterFlags()),
+	Before: withConfig("repos", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		githubRepos, err := g.ListRepos(ctx, getRepoFilter(c))
+		if err != nil {
+			return
+		}
+
+		repos, err := model.LoadRepos(c.String("repos"), githubRepos)
+		if err != nil {
+			return
+		}
+
+		names := make([]string, 0, len(repos))
+		for name := range repos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		now := time.Now()
+		policy := getConfig(c).Stale
+		counts := make(map[string]int)
+		for _, name := range names {
+			ts, err := g.TriageStale(ctx, name, policy.Merge(repos[name].Stale), now, c.Bool("dry-run"))
+			if err != nil {
+				return err
+			}
+			for _, t := range ts {
+				kind := "issue"
+				if t.PullRequest {
+					kind = "pull request"
+				}
+				fmt.Printf("%s#%d: %s %s %q %s\n", t.Repo, t.Number, t.Action, kind, t.Title, t.URL)
+				counts[t.Action]++
+			}
+		}
+		fmt.Printf("Marked %d, unmarked %d, closed %d\n",
+			counts[model.StaleMark], counts[model.StaleUnmark], counts[model.StaleClose])
+		return nil
+	},
+}
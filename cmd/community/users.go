@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/beyondstorage/go-community/env"
+	"github.com/beyondstorage/go-community/model"
+	"github.com/beyondstorage/go-community/services"
+)
+
+var usersCmd = &cli.Command{
+	Name:  "users",
+	Usage: "maintain community users registry",
+	Subcommands: []*cli.Command{
+		usersValidateCmd,
+	},
+}
+
+var usersValidateCmd = &cli.Command{
+	Name:  "validate",
+	Usage: "validate users.toml against github",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "users",
+			Usage: "path to the users.toml",
+			Value: "users.toml",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "github organization name",
+			EnvVars: []string{
+				env.GithubOwner,
+			},
+		},
+		&cli.StringFlag{
+			Name:  "token",
+			Usage: "github access token",
+			EnvVars: []string{
+				env.GithubAccessToken,
+			},
+		},
+	},
+	Before: withConfig("users", "owner", "token"),
+	Action: func(c *cli.Context) (err error) {
+		ctx := context.Background()
+
+		g, err := services.NewGithub(
+			c.String("owner"),
+			c.String("token"))
+		if err != nil {
+			return
+		}
+
+		users, err := model.LoadUsers(c.String("users"))
+		if err != nil {
+			return
+		}
+
+		logins := make([]string, 0, len(users))
+		for login := range users {
+			logins = append(logins, login)
+		}
+		sort.Strings(logins)
+
+		problems := 0
+		for _, login := range logins {
+			if err := users[login].Validate(); err != nil {
+				fmt.Println(err)
+				problems++
+			}
+
+			exist, err := g.UserExists(ctx, login)
+			if err != nil {
+				return err
+			}
+			if !exist {
+				fmt.Printf("user %s is not found on github\n", login)
+				problems++
+			}
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("found %d problems", problems)
+		}
+		fmt.Printf("All %d users are valid\n", len(users))
+		return nil
+	},
+}
+
+// loadUsersIfExist loads users registry, an empty registry will be returned
+// if the file doesn't exist and is not set explicitly.
+func loadUsersIfExist(c *cli.Context) (model.Users, error) {
+	path := c.String("users")
+
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) && !c.IsSet("users") {
+		return model.Users{}, nil
+	}
+	return model.LoadUsers(path)
+}
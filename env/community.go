@@ -0,0 +1,5 @@
+package env
+
+const (
+	Config = "COMMUNITY_CONFIG"
+)
@@ -0,0 +1,10 @@
+package env
+
+const (
+	// SecretsKey is the base64 encoded key of the encrypted secrets file.
+	SecretsKey = "COMMUNITY_SECRETS_KEY"
+	// SecretPrefix and VariablePrefix are prefixes of env which override
+	// values in the secrets file, like COMMUNITY_SECRET_CODECOV_TOKEN.
+	SecretPrefix   = "COMMUNITY_SECRET_"
+	VariablePrefix = "COMMUNITY_VARIABLE_"
+)
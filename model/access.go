@@ -0,0 +1,77 @@
+package model
+
+import (
+	"sort"
+)
+
+// Sources of repo access.
+const (
+	AccessTeam    = "team"
+	AccessDirect  = "direct"
+	AccessOutside = "outside"
+	AccessOrgRole = "org-role"
+)
+
+// Access is a grant which gives a user access to a repo.
+type Access struct {
+	Login  string `json:"login"`
+	Source string `json:"source"`
+	// Team is the team which grants the access, only set for team access.
+	Team       string `json:"team,omitempty"`
+	Permission string `json:"permission"`
+	// Explained means the access is granted by teams.toml.
+	Explained bool `json:"explained"`
+}
+
+// ExplainAccess marks accesses of the repo which are explained by teams,
+// and sorts them by login.
+//
+// Team access is explained if the user is a member of the team (or of a
+// child team) in teams.toml, the permission doesn't exceed the role of the
+// team, and the team or any of its parents has a project of the repo. Admin
+// teams without project apply to all repos. Org owners are always explained,
+// direct grants and outside collaborators are never explained.
+func ExplainAccess(repo Repo, teams Teams, as []Access) {
+	projects := make(map[string]struct{})
+	for _, v := range repo.Project {
+		projects[v] = struct{}{}
+	}
+
+	for i, a := range as {
+		switch a.Source {
+		case AccessOrgRole:
+			as[i].Explained = true
+		case AccessTeam:
+			t, ok := teams[a.Team]
+			if !ok || !teams.hasMember(a.Team, a.Login) ||
+				permissionRank(a.Permission) > permissionRank(t.Role.Permission()) {
+				continue
+			}
+			if t.Project == "" && t.Role == RoleAdmin {
+				as[i].Explained = true
+				continue
+			}
+			as[i].Explained = teams.ownsProject(a.Team, projects)
+		}
+	}
+
+	sort.SliceStable(as, func(i, j int) bool {
+		if as[i].Login != as[j].Login {
+			return as[i].Login < as[j].Login
+		}
+		return as[i].Source < as[j].Source
+	})
+}
+
+// hasMember checks whether login is a member of the team or its children,
+// as github lists members of child teams in the parent team.
+func (t Teams) hasMember(name, login string) bool {
+	for _, n := range append([]string{name}, t.Descendants(name)...) {
+		for _, v := range t[n].Members {
+			if v == login {
+				return true
+			}
+		}
+	}
+	return false
+}
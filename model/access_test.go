@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainAccess(t *testing.T) {
+	teams := Teams{
+		"pmc":                  {Role: RoleAdmin, Members: []string{"xuanwo"}},
+		"go-storage":           {Project: "go-storage", Role: RoleReviewer, Members: []string{"alice"}},
+		"go-storage-committer": {Parent: "go-storage", Role: RoleCommitter, Members: []string{"bob", "frank"}},
+		"go-service":           {Project: "go-service", Role: RoleCommitter, Members: []string{"carol"}},
+	}
+	repo := Repo{Name: "go-storage", Project: []string{"go-storage"}}
+
+	as := []Access{
+		{Login: "eve", Source: AccessOutside, Permission: "push"},
+		{Login: "bob", Source: AccessTeam, Team: "go-storage-committer", Permission: "push"},
+		// members of child teams are listed in the parent team
+		{Login: "bob", Source: AccessTeam, Team: "go-storage", Permission: "triage"},
+		{Login: "alice", Source: AccessTeam, Team: "go-storage", Permission: "triage"},
+		{Login: "carol", Source: AccessTeam, Team: "go-service", Permission: "push"},
+		{Login: "dave", Source: AccessTeam, Team: "legacy", Permission: "admin"},
+		{Login: "alice", Source: AccessDirect, Permission: "admin"},
+		{Login: "owner", Source: AccessOrgRole, Permission: "admin"},
+		{Login: "xuanwo", Source: AccessTeam, Team: "pmc", Permission: "admin"},
+		// hand-added member of a managed team
+		{Login: "mallory", Source: AccessTeam, Team: "go-storage", Permission: "triage"},
+		// team permission escalated on github
+		{Login: "frank", Source: AccessTeam, Team: "go-storage-committer", Permission: "admin"},
+	}
+	ExplainAccess(repo, teams, as)
+
+	assert.Equal(t, []Access{
+		{Login: "alice", Source: AccessDirect, Permission: "admin"},
+		{Login: "alice", Source: AccessTeam, Team: "go-storage", Permission: "triage", Explained: true},
+		{Login: "bob", Source: AccessTeam, Team: "go-storage-committer", Permission: "push", Explained: true},
+		{Login: "bob", Source: AccessTeam, Team: "go-storage", Permission: "triage", Explained: true},
+		{Login: "carol", Source: AccessTeam, Team: "go-service", Permission: "push"},
+		{Login: "dave", Source: AccessTeam, Team: "legacy", Permission: "admin"},
+		{Login: "eve", Source: AccessOutside, Permission: "push"},
+		{Login: "frank", Source: AccessTeam, Team: "go-storage-committer", Permission: "admin"},
+		{Login: "mallory", Source: AccessTeam, Team: "go-storage", Permission: "triage"},
+		{Login: "owner", Source: AccessOrgRole, Permission: "admin", Explained: true},
+		{Login: "xuanwo", Source: AccessTeam, Team: "pmc", Permission: "admin", Explained: true},
+	}, as)
+}
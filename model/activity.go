@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Activity records contributions of a user with links as evidence.
+type Activity struct {
+	Commits   []string
+	MergedPRs []string
+	Reviews   []string
+	Triages   []string
+}
+
+// Activities records activity by github login.
+type Activities map[string]*Activity
+
+// Get returns the activity of login, and creates it if not exist.
+func (as Activities) Get(login string) *Activity {
+	a, ok := as[login]
+	if !ok {
+		a = &Activity{}
+		as[login] = a
+	}
+	return a
+}
+
+// Merge merges all activities of x into as.
+func (as Activities) Merge(x Activities) {
+	for login, v := range x {
+		a := as.Get(login)
+		a.Commits = append(a.Commits, v.Commits...)
+		a.MergedPRs = append(a.MergedPRs, v.MergedPRs...)
+		a.Reviews = append(a.Reviews, v.Reviews...)
+		a.Triages = append(a.Triages, v.Triages...)
+	}
+}
+
+// LastActivity is the most recent activity of a user.
+type LastActivity struct {
+	// Kind is one of commit, review and comment.
+	Kind string
+	At   time.Time
+	URL  string
+}
+
+// Update replaces a with the given activity if it's more recent.
+func (a *LastActivity) Update(kind string, at time.Time, url string) {
+	if at.After(a.At) {
+		a.Kind, a.At, a.URL = kind, at, url
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivities_Merge(t *testing.T) {
+	as := make(Activities)
+	as.Get("alice").MergedPRs = []string{"pr1"}
+
+	as.Merge(Activities{
+		"alice": {MergedPRs: []string{"pr2"}, Reviews: []string{"review1"}},
+		"bob":   {Triages: []string{"issue1"}},
+	})
+
+	assert.Equal(t, []string{"pr1", "pr2"}, as["alice"].MergedPRs)
+	assert.Equal(t, []string{"review1"}, as["alice"].Reviews)
+	assert.Equal(t, []string{"issue1"}, as["bob"].Triages)
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AttentionItem is an open issue without maintainer response or a pull
+// request awaiting its first review.
+type AttentionItem struct {
+	Repo        string
+	Number      int
+	Title       string
+	URL         string
+	Author      string
+	PullRequest bool
+	CreatedAt   time.Time
+}
+
+// Attention is the queue of neglected items of a project, routed to the
+// responsible team of the project.
+type Attention struct {
+	// Project is empty for repos without project.
+	Project string
+	// Team is empty if no team in teams.toml owns the project.
+	Team         string
+	Issues       []AttentionItem
+	PullRequests []AttentionItem
+}
+
+// IsResponder checks whether login could respond to issues and pull requests
+// of the projects, which means reviewer role and above.
+func (t Teams) IsResponder(projects []string, login string) bool {
+	if len(projects) == 0 {
+		projects = []string{""}
+	}
+	for _, p := range projects {
+		if t.RoleOf(p, login).rank() >= RoleReviewer.rank() {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAttentions groups items by projects of their repos, items of repos
+// with several projects are listed in every project. Projects are sorted by
+// name and items from the oldest.
+func NewAttentions(repos Repos, teams Teams, items []AttentionItem) []Attention {
+	m := make(map[string]*Attention)
+	for _, item := range items {
+		projects := repos[item.Repo].Project
+		if len(projects) == 0 {
+			projects = []string{""}
+		}
+		for _, p := range projects {
+			a, ok := m[p]
+			if !ok {
+				a = &Attention{Project: p}
+				if p != "" {
+					a.Team = teams.responsibleTeam(p)
+				}
+				m[p] = a
+			}
+			if item.PullRequest {
+				a.PullRequests = append(a.PullRequests, item)
+			} else {
+				a.Issues = append(a.Issues, item)
+			}
+		}
+	}
+
+	as := make([]Attention, 0, len(m))
+	for _, a := range m {
+		sortAttentionItems(a.Issues)
+		sortAttentionItems(a.PullRequests)
+		as = append(as, *a)
+	}
+	sort.Slice(as, func(i, j int) bool {
+		return as[i].Project < as[j].Project
+	})
+	return as
+}
+
+// responsibleTeam returns the team of the project with the highest role,
+// empty if the project has no team.
+func (t Teams) responsibleTeam(project string) string {
+	team := ""
+	for _, name := range t.ProjectTeams([]string{project}) {
+		if team == "" || t[name].Role.rank() > t[team].Role.rank() {
+			team = name
+		}
+	}
+	return team
+}
+
+func sortAttentionItems(items []AttentionItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].URL < items[j].URL
+	})
+}
+
+// FormatPrint formats the attention as markdown, the team is mentioned so
+// that it will be notified.
+func (a Attention) FormatPrint(owner string, now time.Time) string {
+	b := &strings.Builder{}
+	switch {
+	case a.Project == "":
+		b.WriteString("\n## Repos without project\n")
+	case a.Team == "":
+		fmt.Fprintf(b, "\n## %s\n\nNo team in teams.toml owns this project.\n", a.Project)
+	default:
+		fmt.Fprintf(b, "\n## %s\n\nRouted to @%s/%s\n", a.Project, owner, a.Team)
+	}
+
+	sections := []struct {
+		title string
+		items []AttentionItem
+	}{
+		{"Issues without response", a.Issues},
+		{"Pull requests awaiting review", a.PullRequests},
+	}
+	for _, s := range sections {
+		if len(s.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "\n### %s\n\n", s.title)
+		for _, v := range s.items {
+			fmt.Fprintf(b, "- %s#%d [%s](%s) by @%s, opened %d days ago\n",
+				v.Repo, v.Number, v.Title, v.URL, v.Author, days(now.Sub(v.CreatedAt)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeams_IsResponder(t *testing.T) {
+	assert.True(t, maintainersTeams.IsResponder([]string{"go-storage"}, "carol"))
+	assert.False(t, maintainersTeams.IsResponder([]string{"go-service"}, "carol"))
+	// admin teams without project respond to all repos.
+	assert.True(t, maintainersTeams.IsResponder(nil, "xuanwo"))
+	assert.False(t, maintainersTeams.IsResponder(nil, "frank"))
+}
+
+func TestNewAttentions(t *testing.T) {
+	now := time.Date(2021, 8, 16, 8, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repos := Repos{
+		"go-storage":    {Name: "go-storage", Project: []string{"go-storage"}},
+		"go-service-s3": {Name: "go-service-s3", Project: []string{"go-service", "go-storage"}},
+		"community":     {Name: "community"},
+		"go-cache":      {Name: "go-cache", Project: []string{"go-cache"}},
+	}
+	items := []AttentionItem{
+		{Repo: "go-storage", Number: 2, Title: "Add api", URL: "https://github.com/beyondstorage/go-storage/pull/2", Author: "bob", PullRequest: true, CreatedAt: now.Add(-3 * day)},
+		{Repo: "go-storage", Number: 1, Title: "Panic", URL: "https://github.com/beyondstorage/go-storage/issues/1", Author: "frank", CreatedAt: now.Add(-5 * day)},
+		{Repo: "go-service-s3", Number: 3, Title: "Timeout", URL: "https://github.com/beyondstorage/go-service-s3/issues/3", Author: "grace", CreatedAt: now.Add(-10 * day)},
+		{Repo: "go-cache", Number: 5, Title: "Leak", URL: "https://github.com/beyondstorage/go-cache/issues/5", Author: "ivan", CreatedAt: now.Add(-6 * day)},
+		{Repo: "community", Number: 4, Title: "Question", URL: "https://github.com/beyondstorage/community/issues/4", Author: "heidi", CreatedAt: now.Add(-4 * day)},
+	}
+
+	as := NewAttentions(repos, maintainersTeams, items)
+	assert.Len(t, as, 4)
+	assert.Equal(t, "", as[0].Project)
+	assert.Equal(t, "go-cache", as[1].Project)
+	assert.Equal(t, "", as[1].Team)
+	assert.Equal(t, "go-service", as[2].Project)
+	assert.Equal(t, "go-service-maintainer", as[2].Team)
+	assert.Equal(t, "go-storage", as[3].Project)
+
+	assert.Equal(t, `
+## go-storage
+
+Routed to @beyondstorage/go-storage-maintainer
+
+### Issues without response
+
+- go-service-s3#3 [Timeout](https://github.com/beyondstorage/go-service-s3/issues/3) by @grace, opened 10 days ago
+- go-storage#1 [Panic](https://github.com/beyondstorage/go-storage/issues/1) by @frank, opened 5 days ago
+
+### Pull requests awaiting review
+
+- go-storage#2 [Add api](https://github.com/beyondstorage/go-storage/pull/2) by @bob, opened 3 days ago
+`, as[3].FormatPrint("beyondstorage", now))
+
+	assert.Equal(t, `
+## go-cache
+
+No team in teams.toml owns this project.
+
+### Issues without response
+
+- go-cache#5 [Leak](https://github.com/beyondstorage/go-cache/issues/5) by @ivan, opened 6 days ago
+`, as[1].FormatPrint("beyondstorage", now))
+
+	assert.Equal(t, `
+## Repos without project
+
+### Issues without response
+
+- community#4 [Question](https://github.com/beyondstorage/community/issues/4) by @heidi, opened 4 days ago
+`, as[0].FormatPrint("beyondstorage", now))
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the project level configuration loaded from community.toml.
+//
+// All paths are relative to the working directory.
+type Config struct {
+	Owner string `toml:"owner"`
+
+	Teams   string `toml:"teams"`
+	Repos   string `toml:"repos"`
+	Users   string `toml:"users"`
+	Labels  string `toml:"labels"`
+	Actions string `toml:"actions"`
+	// Secrets is the encrypted file of secret and variable values.
+	Secrets string `toml:"secrets"`
+	// Snapshots is the JSON lines file of report snapshots.
+	Snapshots string `toml:"snapshots"`
+
+	Discovery      RepoFilter          `toml:"discovery"`
+	Contributors   ContributorPolicy   `toml:"contributors"`
+	Promotion      PromotionPolicy     `toml:"promotion"`
+	UnmanagedTeams UnmanagedTeamPolicy `toml:"unmanaged_teams"`
+	Stale          StalePolicy         `toml:"stale"`
+	Matrix         ConfigMatrix        `toml:"matrix"`
+	Report         ConfigReport        `toml:"report"`
+
+	// Command carries flag defaults per command, keyed by the command's
+	// full name like "report weekly".
+	Command map[string]map[string]interface{} `toml:"command"`
+}
+
+type ConfigMatrix struct {
+	HomeServerURL string `toml:"home_server_url"`
+	HomeServer    string `toml:"home_server"`
+	UserID        string `toml:"user_id"`
+}
+
+type ConfigReport struct {
+	Type   string `toml:"type"`
+	Output string `toml:"output"`
+}
+
+func LoadConfig(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read file %s: %v", path, err)
+	}
+
+	var x Config
+	md, err := toml.Decode(string(data), &x)
+	if err != nil {
+		return Config{}, fmt.Errorf("toml unmarshal: %v", err)
+	}
+	if keys := md.Undecoded(); len(keys) > 0 {
+		// Flag defaults are free-form, every other key must be known.
+		for _, k := range keys {
+			if len(k) > 0 && k[0] == "command" {
+				continue
+			}
+			return Config{}, fmt.Errorf("unknown key %s in %s", k, path)
+		}
+	}
+
+	return x, nil
+}
+
+// Defaults returns the flag defaults declared for the command.
+func (c Config) Defaults(command string) map[string]string {
+	m := make(map[string]string)
+	for k, v := range c.Command[command] {
+		switch x := v.(type) {
+		case []interface{}:
+			s := make([]string, 0, len(x))
+			for _, e := range x {
+				s = append(s, fmt.Sprint(e))
+			}
+			m[k] = strings.Join(s, ",")
+		default:
+			m[k] = fmt.Sprint(x)
+		}
+	}
+	return m
+}
+
+// Validate checks that the config is complete and all referenced files
+// can be loaded.
+func (c Config) Validate() error {
+	if c.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+
+	if c.Teams != "" {
+		if _, err := LoadTeams(c.Teams); err != nil {
+			return fmt.Errorf("teams: %w", err)
+		}
+	}
+	if c.Repos != "" {
+		if _, err := LoadRepos(c.Repos, nil); err != nil {
+			return fmt.Errorf("repos: %w", err)
+		}
+	}
+	if c.Users != "" {
+		users, err := LoadUsers(c.Users)
+		if err != nil {
+			return fmt.Errorf("users: %w", err)
+		}
+		for _, u := range users {
+			if err := u.Validate(); err != nil {
+				return fmt.Errorf("users: %w", err)
+			}
+		}
+	}
+	if c.Labels != "" {
+		if _, err := os.Stat(c.Labels); err != nil {
+			return fmt.Errorf("labels: %w", err)
+		}
+	}
+	if c.Actions != "" {
+		fi, err := os.Stat(c.Actions)
+		if err != nil {
+			return fmt.Errorf("actions: %w", err)
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("actions: %s is not a directory", c.Actions)
+		}
+	}
+
+	if err := c.UnmanagedTeams.Validate(); err != nil {
+		return fmt.Errorf("unmanaged_teams: %w", err)
+	}
+
+	m := c.Matrix
+	if (m.HomeServerURL == "") != (m.HomeServer == "") || (m.HomeServer == "") != (m.UserID == "") {
+		return fmt.Errorf("matrix: home_server_url, home_server and user_id must be set together")
+	}
+	return nil
+}
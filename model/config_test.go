@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	x, err := LoadConfig("testdata/community.toml")
+	if err != nil {
+		t.Fatal("load config", err)
+	}
+
+	assert.Equal(t, "beyondstorage", x.Owner)
+	assert.Equal(t, "testdata/teams.toml", x.Teams)
+	assert.Equal(t, "community", x.Report.Output)
+	assert.Equal(t, 2, x.Contributors.MinMergedPRs)
+	assert.Equal(t, Duration(180*24*time.Hour), x.Contributors.Window)
+	assert.Equal(t, RepoFilter{ExcludeForks: true, Topics: []string{"storage"}}, x.Discovery)
+	assert.Equal(t, map[string]string{"repo": "go-service-*"}, x.Defaults("track"))
+	assert.Equal(t, UnmanagedRevoke, x.UnmanagedTeams.GetMode())
+	assert.True(t, x.UnmanagedTeams.IsAllowed("sig-docs"))
+	assert.False(t, x.UnmanagedTeams.IsAllowed("legacy"))
+	assert.Equal(t, 90*24*time.Hour, x.Stale.GetInactive())
+	assert.Equal(t, []string{"pinned", "security"}, x.Stale.ExemptLabels)
+	assert.NoError(t, x.Validate())
+}
+
+func TestConfig_Validate(t *testing.T) {
+	x := Config{Owner: "beyondstorage", Teams: "testdata/not-exist.toml"}
+	assert.Error(t, x.Validate())
+
+	x = Config{}
+	assert.Error(t, x.Validate())
+
+	x = Config{Owner: "beyondstorage", UnmanagedTeams: UnmanagedTeamPolicy{Mode: "prune"}}
+	assert.Error(t, x.Validate())
+}
@@ -0,0 +1,56 @@
+package model
+
+import "sort"
+
+// ContributorPolicy decides which contributors will be invited into the org.
+//
+// Contributors reaching either MinMergedPRs or MinContributions are invited,
+// zero thresholds are not checked and all contributors are invited if both
+// are zero.
+type ContributorPolicy struct {
+	// MinMergedPRs is the minimum number of merged PRs in the org within Window.
+	MinMergedPRs int `toml:"min_merged_prs"`
+	// MinContributions is the minimum number of commits across all repos.
+	// Github only reports all time contributions, so Window doesn't apply.
+	MinContributions int `toml:"min_contributions"`
+	// Window limits the merged PRs to count, zero means all time.
+	Window Duration `toml:"window"`
+	// Exclude lists logins which will never be invited.
+	Exclude []string `toml:"exclude"`
+	// MaxInvites caps invitations sent in one run, zero means unlimited.
+	MaxInvites int `toml:"max_invites"`
+	// Team is the slug of the team invitees will be added to.
+	Team string `toml:"team"`
+	// InvitationExpire cancels pending invitations older than it, zero
+	// means invitations never expire.
+	InvitationExpire Duration `toml:"invitation_expire"`
+
+	Welcome WelcomePolicy `toml:"welcome"`
+}
+
+// Contributor is a github user who contributed to repos in the org.
+type Contributor struct {
+	Login         string
+	ID            int64
+	Contributions int
+}
+
+func (p ContributorPolicy) IsExcluded(login string) bool {
+	for _, v := range p.Exclude {
+		if v == login {
+			return true
+		}
+	}
+	return false
+}
+
+// SortContributors sorts contributors by contributions, the most active
+// contributor will be invited first when MaxInvites is set.
+func SortContributors(cs []Contributor) {
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Contributions != cs[j].Contributions {
+			return cs[i].Contributions > cs[j].Contributions
+		}
+		return cs[i].Login < cs[j].Login
+	})
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortContributors(t *testing.T) {
+	cs := []Contributor{
+		{Login: "b", Contributions: 1},
+		{Login: "c", Contributions: 5},
+		{Login: "a", Contributions: 1},
+	}
+	SortContributors(cs)
+
+	assert.Equal(t, []Contributor{
+		{Login: "c", Contributions: 5},
+		{Login: "a", Contributions: 1},
+		{Login: "b", Contributions: 1},
+	}, cs)
+}
+
+func TestContributorPolicy_IsExcluded(t *testing.T) {
+	p := ContributorPolicy{Exclude: []string{"spammer"}}
+
+	assert.True(t, p.IsExcluded("spammer"))
+	assert.False(t, p.IsExcluded("contributor"))
+}
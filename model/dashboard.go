@@ -0,0 +1,159 @@
+package model
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+// CI status of the default branch, empty means no checks.
+const (
+	CIStatusSuccess = "success"
+	CIStatusFailure = "failure"
+	CIStatusPending = "pending"
+)
+
+// Action sync status of a repo.
+const (
+	ActionSyncInSync    = "in sync"
+	ActionSyncOutOfSync = "out of sync"
+	// ActionSyncUnmanaged is for repos without required actions.
+	ActionSyncUnmanaged = "unmanaged"
+)
+
+// PullRequestRef is a pull request shown in dashboard.
+type PullRequestRef struct {
+	Number    int
+	Title     string
+	URL       string
+	CreatedAt time.Time
+}
+
+// ReleaseRef is a release shown in dashboard.
+type ReleaseRef struct {
+	Tag         string
+	URL         string
+	PublishedAt time.Time
+}
+
+// RepoHealth is the health of a repo shown in dashboard.
+type RepoHealth struct {
+	Name string
+
+	OpenIssues int
+	OpenPRs    int
+	// OldestUnreviewedPR is nil if all open pull requests are reviewed.
+	OldestUnreviewedPR *PullRequestRef
+	// LastRelease is nil if the repo has never been released.
+	LastRelease *ReleaseRef
+
+	CIStatus   string
+	ActionSync string
+	Teams      []string
+}
+
+// Dashboard is the health of all repos of the org.
+type Dashboard struct {
+	Owner       string
+	GeneratedAt time.Time
+	Repos       []RepoHealth
+	Weeks       []WeeklyStatistic
+}
+
+// ProjectTeams returns sorted names of teams of the given projects, child
+// teams own the projects of their parents as well.
+func (t Teams) ProjectTeams(projects []string) []string {
+	ps := make(map[string]struct{}, len(projects))
+	for _, v := range projects {
+		ps[v] = struct{}{}
+	}
+
+	names := make([]string, 0)
+	for name := range t {
+		if t.ownsProject(name, ps) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ownsProject checks whether the team or any of its parents has one of the
+// projects.
+func (t Teams) ownsProject(name string, projects map[string]struct{}) bool {
+	team, ok := t[name]
+	for depth := 0; ok && depth <= len(t); depth++ {
+		if _, has := projects[team.Project]; has && team.Project != "" {
+			return true
+		}
+		if team.Parent == "" {
+			break
+		}
+		team, ok = t[team.Parent]
+	}
+	return false
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"days": func(now, t time.Time) int {
+		return int(now.Sub(t).Hours() / 24)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Owner}} community dashboard</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+.success, .in-sync { color: #1a7f37; }
+.failure, .out-of-sync { color: #cf222e; }
+.pending { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>{{.Owner}} community dashboard</h1>
+<p>Generated at {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+{{- $now := .GeneratedAt}}
+{{- with .Weeks}}
+<h2>Weekly trends</h2>
+<table>
+<tr><th>Week</th><th>PRs opened</th><th>PRs closed</th><th>Issues opened</th><th>Issues closed</th></tr>
+{{- range .}}
+<tr><td>{{.Week}}</td><td>{{.PROpened}}</td><td>{{.PRClosed}}</td><td>{{.IssueOpened}}</td><td>{{.IssueClosed}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h2>Repos</h2>
+<table>
+<tr><th>Repo</th><th>Open issues</th><th>Open PRs</th><th>Oldest unreviewed PR</th><th>Last release</th><th>CI</th><th>Actions</th><th>Teams</th></tr>
+{{- range .Repos}}
+<tr>
+<td><a href="https://github.com/{{$.Owner}}/{{.Name}}">{{.Name}}</a></td>
+<td>{{.OpenIssues}}</td>
+<td>{{.OpenPRs}}</td>
+<td>{{with .OldestUnreviewedPR}}<a href="{{.URL}}" title="{{.Title}}">#{{.Number}}</a> ({{days $now .CreatedAt}} days){{else}}-{{end}}</td>
+<td>{{with .LastRelease}}<a href="{{.URL}}">{{.Tag}}</a> ({{date .PublishedAt}}){{else}}-{{end}}</td>
+<td class="{{.CIStatus}}">{{or .CIStatus "-"}}</td>
+<td class="{{if eq .ActionSync "in sync"}}in-sync{{else if eq .ActionSync "out of sync"}}out-of-sync{{end}}">{{or .ActionSync "-"}}</td>
+<td>{{range $i, $v := .Teams}}{{if $i}}, {{end}}<a href="https://github.com/orgs/{{$.Owner}}/teams/{{$v}}">{{$v}}</a>{{else}}-{{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// RenderDashboard renders the dashboard as a static html page, repos are
+// sorted by name.
+func RenderDashboard(w io.Writer, d Dashboard) error {
+	sort.Slice(d.Repos, func(i, j int) bool {
+		return d.Repos[i].Name < d.Repos[j].Name
+	})
+	return dashboardTemplate.Execute(w, d)
+}
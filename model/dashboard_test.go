@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectTeams(t *testing.T) {
+	assert.Equal(t, []string{
+		"go-storage-committer",
+		"go-storage-empty",
+		"go-storage-maintainer",
+		"go-storage-reviewer",
+	}, maintainersTeams.ProjectTeams([]string{"go-storage"}))
+	assert.Empty(t, maintainersTeams.ProjectTeams(nil))
+
+	// Child teams get the project from their parents.
+	nested := Teams{
+		"go-storage-maintainer": {Project: "go-storage", Role: RoleMaintainer},
+		"go-storage-release":    {Parent: "go-storage-maintainer", Role: RoleCommitter},
+		"go-storage-release-ci": {Parent: "go-storage-release", Role: RoleReviewer},
+		"go-service-maintainer": {Project: "go-service", Role: RoleMaintainer},
+	}
+	assert.Equal(t, []string{
+		"go-storage-maintainer",
+		"go-storage-release",
+		"go-storage-release-ci",
+	}, nested.ProjectTeams([]string{"go-storage"}))
+	assert.Equal(t, "go-storage-maintainer", nested.responsibleTeam("go-storage"))
+}
+
+func TestRenderDashboard(t *testing.T) {
+	now := time.Date(2021, 8, 16, 8, 0, 0, 0, time.UTC)
+	d := Dashboard{
+		Owner:       "beyondstorage",
+		GeneratedAt: now,
+		Repos: []RepoHealth{
+			{
+				Name:       "go-storage",
+				OpenIssues: 12,
+				OpenPRs:    3,
+				OldestUnreviewedPR: &PullRequestRef{
+					Number:    42,
+					Title:     "feat: <script>",
+					URL:       "https://github.com/beyondstorage/go-storage/pull/42",
+					CreatedAt: now.Add(-10 * 24 * time.Hour),
+				},
+				LastRelease: &ReleaseRef{
+					Tag:         "v4.5.0",
+					URL:         "https://github.com/beyondstorage/go-storage/releases/tag/v4.5.0",
+					PublishedAt: now.Add(-30 * 24 * time.Hour),
+				},
+				CIStatus:   CIStatusFailure,
+				ActionSync: ActionSyncOutOfSync,
+				Teams:      []string{"go-storage-maintainer"},
+			},
+			{
+				Name:       "community",
+				ActionSync: ActionSyncUnmanaged,
+			},
+		},
+		Weeks: []WeeklyStatistic{
+			{Week: "2021-W32", Statistic: Statistic{PROpened: 8}},
+		},
+	}
+
+	b := &strings.Builder{}
+	assert.NoError(t, RenderDashboard(b, d))
+	out := b.String()
+
+	assert.Contains(t, out, `<tr><td>2021-W32</td><td>8</td><td>0</td><td>0</td><td>0</td></tr>`)
+	assert.Contains(t, out, `<a href="https://github.com/beyondstorage/go-storage/pull/42" title="feat: &lt;script&gt;">#42</a> (10 days)`)
+	assert.Contains(t, out, `<a href="https://github.com/beyondstorage/go-storage/releases/tag/v4.5.0">v4.5.0</a> (2021-07-17)`)
+	assert.Contains(t, out, `<td class="failure">failure</td>`)
+	assert.Contains(t, out, `<td class="out-of-sync">out of sync</td>`)
+	assert.Contains(t, out, `<a href="https://github.com/orgs/beyondstorage/teams/go-storage-maintainer">go-storage-maintainer</a>`)
+	// Repos are sorted by name.
+	assert.Less(t, strings.Index(out, ">community<"), strings.Index(out, ">go-storage<"))
+}
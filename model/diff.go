@@ -0,0 +1,36 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UnifiedDiff returns the unified diff from a to b of the file at path, nil
+// content means the file doesn't exist. Empty string means no difference.
+func UnifiedDiff(path string, a, b []byte) (string, error) {
+	from, to := "a/"+path, "b/"+path
+	if a == nil {
+		from = "/dev/null"
+	}
+	if b == nil {
+		to = "/dev/null"
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+}
+
+// splitLines splits content into lines with line breaks kept.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
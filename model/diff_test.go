@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []byte("name: test\non: [push]\njobs: {}\n")
+	b := []byte("name: test\non: [push, pull_request]\njobs: {}\n")
+
+	d, err := UnifiedDiff(".github/workflows/test.yml", a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, `--- a/.github/workflows/test.yml
++++ b/.github/workflows/test.yml
+@@ -1,3 +1,3 @@
+ name: test
+-on: [push]
++on: [push, pull_request]
+ jobs: {}
+`, d)
+
+	d, err = UnifiedDiff("test.yml", nil, []byte("name: test\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "--- /dev/null\n+++ b/test.yml\n@@ -0,0 +1 @@\n+name: test\n", d)
+
+	d, err = UnifiedDiff("test.yml", a, a)
+	assert.NoError(t, err)
+	assert.Empty(t, d)
+}
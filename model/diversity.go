@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnknownAffiliation is used for users without affiliation in users registry.
+const UnknownAffiliation = "Unknown"
+
+// Diversity counts contributions of a project by affiliation.
+type Diversity struct {
+	Project     string
+	Commits     map[string]int
+	MergedPRs   map[string]int
+	Reviews     map[string]int
+	Maintainers map[string]int
+}
+
+// NewDiversity builds diversity of a project from activities of its repos,
+// members of maintainer and admin teams of the project are maintainers.
+func NewDiversity(project string, as Activities, teams Teams, users Users) Diversity {
+	d := Diversity{
+		Project:     project,
+		Commits:     make(map[string]int),
+		MergedPRs:   make(map[string]int),
+		Reviews:     make(map[string]int),
+		Maintainers: make(map[string]int),
+	}
+
+	for login, a := range as {
+		aff := users.Affiliation(login)
+		if n := len(a.Commits); n > 0 {
+			d.Commits[aff] += n
+		}
+		if n := len(a.MergedPRs); n > 0 {
+			d.MergedPRs[aff] += n
+		}
+		if n := len(a.Reviews); n > 0 {
+			d.Reviews[aff] += n
+		}
+	}
+
+	maintainers := make(map[string]struct{})
+	for _, t := range teams {
+		if t.Project != project || t.Role.rank() < RoleMaintainer.rank() {
+			continue
+		}
+		for _, v := range t.Members {
+			maintainers[v] = struct{}{}
+		}
+	}
+	for v := range maintainers {
+		d.Maintainers[users.Affiliation(v)]++
+	}
+	return d
+}
+
+// MaintainerOrgs returns the number of distinct organizations with
+// maintainers, unknown affiliation is not counted.
+func (d Diversity) MaintainerOrgs() int {
+	n := 0
+	for aff := range d.Maintainers {
+		if aff != UnknownAffiliation {
+			n++
+		}
+	}
+	return n
+}
+
+// Affiliations returns all affiliations sorted by contributions.
+func (d Diversity) Affiliations() []string {
+	total := make(map[string]int)
+	for _, m := range []map[string]int{d.Commits, d.MergedPRs, d.Reviews, d.Maintainers} {
+		for aff, n := range m {
+			total[aff] += n
+		}
+	}
+
+	affs := make([]string, 0, len(total))
+	for aff := range total {
+		affs = append(affs, aff)
+	}
+	sort.Slice(affs, func(i, j int) bool {
+		if total[affs[i]] != total[affs[j]] {
+			return total[affs[i]] > total[affs[j]]
+		}
+		return affs[i] < affs[j]
+	})
+	return affs
+}
+
+// FormatPrint format diversity as print needed
+func (d Diversity) FormatPrint() string {
+	b := &strings.Builder{}
+	b.WriteString(fmt.Sprintf("## %s\n\n", d.Project))
+	b.WriteString(fmt.Sprintf("Organizations with maintainers: %d\n\n", d.MaintainerOrgs()))
+	b.WriteString("| Affiliation | Commits | Merged PRs | Reviews | Maintainers |\n")
+	b.WriteString("| ---- | ---- | ---- | ---- | ---- |\n")
+	for _, aff := range d.Affiliations() {
+		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", aff,
+			share(d.Commits, aff), share(d.MergedPRs, aff),
+			share(d.Reviews, aff), share(d.Maintainers, aff)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// share renders count of key and its percentage in m.
+func share(m map[string]int, key string) string {
+	total := 0
+	for _, n := range m {
+		total += n
+	}
+	if total == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (%.1f%%)", m[key], float64(m[key])*100/float64(total))
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiversity(t *testing.T) {
+	users := Users{
+		"alice": {Affiliation: "A Inc."},
+		"bob":   {Affiliation: "B Inc."},
+		"carol": {Affiliation: "A Inc."},
+	}
+	teams := Teams{
+		"go-storage-maintainer": {Project: "go-storage", Role: RoleMaintainer, Members: []string{"alice", "bob"}},
+		"go-storage-committer":  {Project: "go-storage", Role: RoleCommitter, Members: []string{"carol"}},
+		"go-service-maintainer": {Project: "go-service", Role: RoleMaintainer, Members: []string{"carol"}},
+	}
+	as := Activities{
+		"alice": {Commits: []string{"c1", "c2", "c3"}, MergedPRs: []string{"pr1"}},
+		"carol": {Commits: []string{"c4"}, Reviews: []string{"r1"}},
+		"dave":  {MergedPRs: []string{"pr2"}},
+	}
+
+	d := NewDiversity("go-storage", as, teams, users)
+	assert.Equal(t, map[string]int{"A Inc.": 4}, d.Commits)
+	assert.Equal(t, map[string]int{"A Inc.": 1, UnknownAffiliation: 1}, d.MergedPRs)
+	assert.Equal(t, map[string]int{"A Inc.": 1, "B Inc.": 1}, d.Maintainers)
+	assert.Equal(t, 2, d.MaintainerOrgs())
+	assert.Equal(t, []string{"A Inc.", "B Inc.", UnknownAffiliation}, d.Affiliations())
+
+	assert.Equal(t, `## go-storage
+
+Organizations with maintainers: 2
+
+| Affiliation | Commits | Merged PRs | Reviews | Maintainers |
+| ---- | ---- | ---- | ---- | ---- |
+| A Inc. | 4 (100.0%) | 1 (50.0%) | 1 (100.0%) | 1 (50.0%) |
+| B Inc. | 0 (0.0%) | 0 (0.0%) | 0 (0.0%) | 1 (50.0%) |
+| Unknown | 0 (0.0%) | 1 (50.0%) | 0 (0.0%) | 0 (0.0%) |
+
+`, d.FormatPrint())
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var durationPattern = regexp.MustCompile(`^(\d+)(d|w|mo|y)$`)
+
+// Duration is a time.Duration which also accepts day based units like
+// "30d", "2w", "6mo" and "1y" in config.
+type Duration time.Duration
+
+// ParseDuration parses day based units, and falls back to time.ParseDuration.
+//
+// A month is counted as 30 days and a year as 365 days.
+func ParseDuration(s string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("parse duration %s: %v", s, err)
+		}
+		return d, nil
+	}
+
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("parse duration %s: %v", s, err)
+	}
+
+	day := 24 * time.Hour
+	switch m[2] {
+	case "w":
+		day *= 7
+	case "mo":
+		day *= 30
+	case "y":
+		day *= 365
+	}
+	return time.Duration(n) * day, nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	x, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(x)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Since returns the time d before now, or the zero time if d is not set.
+func (d Duration) Since(now time.Time) time.Time {
+	if d == 0 {
+		return time.Time{}
+	}
+	return now.Add(-time.Duration(d))
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"6mo": 180 * 24 * time.Hour,
+		"1y":  365 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+	}
+	for input, expected := range cases {
+		d, err := ParseDuration(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, d, input)
+	}
+
+	_, err := ParseDuration("12months")
+	assert.Error(t, err)
+}
+
+func TestDuration_UnmarshalText(t *testing.T) {
+	var x struct {
+		Window Duration `toml:"window"`
+	}
+	_, err := toml.Decode(`window = "90d"`, &x)
+	if err != nil {
+		t.Fatal("decode", err)
+	}
+	assert.Equal(t, Duration(90*24*time.Hour), x.Window)
+
+	now := time.Now()
+	assert.Equal(t, now.Add(-90*24*time.Hour), x.Window.Since(now))
+	assert.True(t, Duration(0).Since(now).IsZero())
+}
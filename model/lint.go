@@ -0,0 +1,395 @@
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gobwas/glob"
+)
+
+// Problem is a lint finding with the position it comes from.
+type Problem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+var validRoles = map[Role]struct{}{
+	RoleAdmin:       {},
+	RoleMaintainer:  {},
+	RoleCommitter:   {},
+	RoleReviewer:    {},
+	RoleContributor: {},
+}
+
+// Lint checks teams.toml, repos.toml and users.toml referenced by config.
+//
+// githubRepos is optional, checks that need the real repo list will be
+// skipped if it's nil.
+func Lint(cfg Config, githubRepos []string) (ps []Problem, err error) {
+	var (
+		teams    Teams
+		teamsPos map[string]int
+		repos    Repos
+		reposPos map[string]int
+		repoKeys []toml.Key
+		users    Users
+		usersPos map[string]int
+	)
+
+	if cfg.Teams != "" {
+		_, teamsPos, ps, err = lintDecode(cfg.Teams, &teams, ps)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Repos != "" {
+		var md toml.MetaData
+		md, reposPos, ps, err = lintDecode(cfg.Repos, &repos, ps)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range md.Keys() {
+			if len(k) == 1 {
+				repoKeys = append(repoKeys, k)
+			}
+		}
+	}
+	if cfg.Users != "" {
+		_, usersPos, ps, err = lintDecode(cfg.Users, &users, ps)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check all users.
+	logins := make([]string, 0, len(users))
+	for login := range users {
+		logins = append(logins, login)
+	}
+	sort.Slice(logins, func(i, j int) bool {
+		return usersPos[toml.Key{logins[i]}.String()] < usersPos[toml.Key{logins[j]}.String()]
+	})
+	for _, login := range logins {
+		u := users[login]
+		u.Login = login
+		if err := u.Validate(); err != nil {
+			ps = append(ps, Problem{cfg.Users, usersPos[toml.Key{login}.String()], err.Error()})
+		}
+	}
+
+	// Check all repo patterns.
+	projects := make(map[string]struct{})
+	globs := make(map[string]glob.Glob)
+	for _, k := range repoKeys {
+		pattern := k[0]
+		for _, v := range repos[pattern].Project {
+			if strings.HasPrefix(v, "-") {
+				continue
+			}
+			projects[v] = struct{}{}
+		}
+
+		action := repos[pattern].Action
+		for i, vs := range [][]string{action.Required, action.Allowed} {
+			key := []string{"required", "allowed"}[i]
+			for _, v := range vs {
+				if _, err := glob.Compile(strings.TrimPrefix(v, "-")); err != nil {
+					ps = append(ps, Problem{cfg.Repos, reposPos[toml.Key{pattern, "action", key}.String()],
+						fmt.Sprintf("invalid action pattern %q: %v", v, err)})
+				}
+			}
+		}
+
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			ps = append(ps, Problem{cfg.Repos, reposPos[k.String()],
+				fmt.Sprintf("invalid glob pattern %q: %v", pattern, err)})
+			continue
+		}
+		globs[pattern] = g
+
+		if githubRepos == nil {
+			continue
+		}
+		matched := false
+		for _, name := range githubRepos {
+			if g.Match(name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			ps = append(ps, Problem{cfg.Repos, reposPos[k.String()],
+				fmt.Sprintf("pattern %q matches no repos", pattern)})
+		}
+	}
+
+	// Check patterns with equal length which match the same repo.
+	if githubRepos != nil {
+		for i, a := range repoKeys {
+			for _, b := range repoKeys[i+1:] {
+				ga, gb := globs[a[0]], globs[b[0]]
+				if ga == nil || gb == nil || len(a[0]) != len(b[0]) {
+					continue
+				}
+				for _, name := range githubRepos {
+					if ga.Match(name) && gb.Match(name) {
+						ps = append(ps, Problem{cfg.Repos, reposPos[b.String()],
+							fmt.Sprintf("pattern %q overlaps with %q on repo %s", b[0], a[0], name)})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Check all teams.
+	names := make([]string, 0, len(teams))
+	for name := range teams {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return teamsPos[toml.Key{names[i]}.String()] < teamsPos[toml.Key{names[j]}.String()]
+	})
+	for _, name := range names {
+		t := teams[name]
+		line := teamsPos[toml.Key{name}.String()]
+
+		if _, ok := validRoles[t.Role]; !ok {
+			if l, ok := teamsPos[toml.Key{name, "role"}.String()]; ok {
+				line = l
+			}
+			ps = append(ps, Problem{cfg.Teams, line,
+				fmt.Sprintf("team %s has invalid role %q", name, t.Role)})
+		}
+
+		if t.Project != "" && cfg.Repos != "" {
+			if _, ok := projects[t.Project]; !ok {
+				ps = append(ps, Problem{cfg.Teams, teamsPos[toml.Key{name, "project"}.String()],
+					fmt.Sprintf("team %s references project %s which no repo has", name, t.Project)})
+			}
+		}
+
+		if t.Privacy != "" && t.Privacy != TeamPrivacyClosed && t.Privacy != TeamPrivacySecret {
+			ps = append(ps, Problem{cfg.Teams, teamsPos[toml.Key{name, "privacy"}.String()],
+				fmt.Sprintf("team %s has invalid privacy %q", name, t.Privacy)})
+		}
+		if t.Parent != "" {
+			if _, ok := teams[t.Parent]; !ok {
+				ps = append(ps, Problem{cfg.Teams, teamsPos[toml.Key{name, "parent"}.String()],
+					fmt.Sprintf("team %s has unknown parent %s", name, t.Parent)})
+			}
+		}
+		members := make(map[string]struct{}, len(t.Members))
+		for _, m := range t.Members {
+			members[m] = struct{}{}
+		}
+		for _, m := range t.Maintainers {
+			if _, ok := members[m]; !ok {
+				ps = append(ps, Problem{cfg.Teams, teamsPos[toml.Key{name, "maintainers"}.String()],
+					fmt.Sprintf("team %s maintainer %s is not a member", name, m)})
+			}
+		}
+
+		if cfg.Users != "" {
+			for _, m := range t.Members {
+				if _, ok := users[m]; ok {
+					continue
+				}
+				ps = append(ps, Problem{cfg.Teams, teamsPos[toml.Key{name, "members"}.String()],
+					fmt.Sprintf("team %s member %s is missing from users", name, m)})
+			}
+		}
+	}
+
+	return ps, nil
+}
+
+// lintDecode decodes path into v and records all undecoded keys as problems.
+func lintDecode(path string, v interface{}, ps []Problem) (toml.MetaData, map[string]int, []Problem, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return toml.MetaData{}, nil, ps, fmt.Errorf("read file %s: %v", path, err)
+	}
+
+	md, err := toml.Decode(string(data), v)
+	if err != nil {
+		if pe, ok := err.(toml.ParseError); ok {
+			return md, nil, append(ps, Problem{path, pe.Position.Line, pe.Error()}), nil
+		}
+		return md, nil, append(ps, Problem{path, 0, err.Error()}), nil
+	}
+
+	pos := keyPositions(data, md.Keys())
+	for _, k := range md.Undecoded() {
+		ps = append(ps, Problem{path, pos[k.String()], fmt.Sprintf("unknown key %s", k)})
+	}
+	return md, pos, ps, nil
+}
+
+// keyPositions returns the line of every table and key in a toml document,
+// keyed by toml.Key.String().
+//
+// keys are reported by the toml decoder in the order of the document, lines
+// are searched forward for each of them and multi-line arrays and strings
+// are skipped, so that their content is not taken as keys. Parents of dotted
+// keys and tables are defined by the same line implicitly.
+func keyPositions(data []byte, keys []toml.Key) map[string]int {
+	pos := make(map[string]int)
+	lines := strings.Split(string(data), "\n")
+
+	var table toml.Key
+	start := 0
+	for _, k := range keys {
+		for i := start; i < len(lines); i++ {
+			line := strings.TrimSpace(lines[i])
+			if line == "" || line[0] == '#' {
+				continue
+			}
+
+			var key toml.Key
+			inline, end := false, i
+			if line[0] == '[' {
+				header := strings.Trim(line, "[]")
+				if idx := strings.LastIndex(line, "]"); idx > 0 {
+					header = strings.Trim(line[:idx], "[]")
+				}
+				key = splitKey(header)
+			} else {
+				idx := strings.Index(line, "=")
+				if idx <= 0 {
+					continue
+				}
+				key = append(append(toml.Key{}, table...), splitKey(line[:idx])...)
+				value := strings.TrimSpace(line[idx+1:])
+				inline = strings.HasPrefix(value, "{")
+				end = valueEnd(lines, i, value)
+			}
+			// Keys of inline tables are defined by the line of the table.
+			if !hasKeyPrefix(key, k) && !(inline && hasKeyPrefix(k, key)) {
+				continue
+			}
+
+			if line[0] == '[' {
+				table = key
+			}
+			for j := 1; j <= len(key); j++ {
+				if _, ok := pos[key[:j].String()]; !ok {
+					pos[key[:j].String()] = i + 1
+				}
+			}
+			if _, ok := pos[k.String()]; !ok {
+				pos[k.String()] = i + 1
+			}
+			start = i
+			if end > i {
+				start = end + 1
+			}
+			break
+		}
+	}
+	return pos
+}
+
+// valueEnd returns the last line of the value starting at line i, which is
+// not i only for multi-line arrays and strings.
+func valueEnd(lines []string, i int, value string) int {
+	for _, delim := range []string{`"""`, "'''"} {
+		if !strings.HasPrefix(value, delim) {
+			continue
+		}
+		if strings.Contains(value[len(delim):], delim) {
+			return i
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.Contains(lines[j], delim) {
+				return j
+			}
+		}
+		return len(lines) - 1
+	}
+	if !strings.HasPrefix(value, "[") {
+		return i
+	}
+
+	// Count brackets outside strings and comments.
+	depth := 0
+	for j, s := i, value; j < len(lines); j++ {
+		if j > i {
+			s = lines[j]
+		}
+		var quote rune
+		escaped := false
+	scan:
+		for _, c := range s {
+			switch {
+			case escaped:
+				escaped = false
+			case quote == '"' && c == '\\':
+				escaped = true
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#':
+				break scan
+			case c == '[':
+				depth++
+			case c == ']':
+				depth--
+			}
+		}
+		if depth <= 0 {
+			return j
+		}
+	}
+	return len(lines) - 1
+}
+
+func hasKeyPrefix(k, prefix toml.Key) bool {
+	if len(k) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if k[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitKey splits a dotted toml key with respect to quotes.
+func splitKey(s string) toml.Key {
+	var (
+		k     toml.Key
+		b     strings.Builder
+		quote rune
+	)
+	for _, c := range s {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			b.WriteRune(c)
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '.':
+			k = append(k, strings.TrimSpace(b.String()))
+			b.Reset()
+		case c == ' ' || c == '\t':
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return append(k, strings.TrimSpace(b.String()))
+}
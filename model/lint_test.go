@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	cfg := Config{
+		Teams: "testdata/lint/teams.toml",
+		Repos: "testdata/lint/repos.toml",
+		Users: "testdata/lint/users.toml",
+	}
+
+	ps, err := Lint(cfg, []string{"go-storage", "go-service-s3", "go-service-gcs"})
+	if err != nil {
+		t.Fatal("lint", err)
+	}
+
+	expected := []string{
+		"testdata/lint/repos.toml:7: unknown key \"go-service-*\".action.requried",
+		"testdata/lint/users.toml:4: user bad has invalid timezone \"Mars/Olympus\"",
+		"testdata/lint/repos.toml:15: invalid action pattern \"release-[a\": unexpected end of input",
+		"testdata/lint/repos.toml:12: invalid glob pattern \"dm-[a\": unexpected end of input",
+		"testdata/lint/repos.toml:9: pattern \"go-*rvice-s3\" overlaps with \"go-service-*\" on repo go-service-s3",
+		"testdata/lint/teams.toml:13: team go-storage-maintainer has unknown parent go-storage",
+		"testdata/lint/teams.toml:12: team go-storage-maintainer maintainer carol is not a member",
+		"testdata/lint/teams.toml:8: team go-storage-maintainer member bob is missing from users",
+		"testdata/lint/teams.toml:17: team go-nothing-committer has invalid role \"commiter\"",
+		"testdata/lint/teams.toml:16: team go-nothing-committer references project go-nothing which no repo has",
+		"testdata/lint/teams.toml:18: team go-nothing-committer has invalid privacy \"public\"",
+	}
+	actual := make([]string, 0, len(ps))
+	for _, p := range ps {
+		actual = append(actual, p.String())
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestLint_WithoutGithubRepos(t *testing.T) {
+	cfg := Config{
+		Repos: "testdata/lint/repos.toml",
+	}
+
+	ps, err := Lint(cfg, nil)
+	if err != nil {
+		t.Fatal("lint", err)
+	}
+	assert.Len(t, ps, 3)
+}
+
+func TestKeyPositions(t *testing.T) {
+	data := []byte(`[go-storage]
+members = [
+  "alice", # [owner]
+  "role = admin",
+]
+description = """
+role = maintainer
+[go-service]
+"""
+role = "maintainer"
+links = {docs = "https://beyondstorage.io"}
+
+[go-service]
+parent.name = "go-storage"
+`)
+	var v map[string]interface{}
+	md, err := toml.Decode(string(data), &v)
+	if err != nil {
+		t.Fatal("toml decode", err)
+	}
+
+	pos := keyPositions(data, md.Keys())
+	assert.Equal(t, 1, pos["go-storage"])
+	assert.Equal(t, 2, pos["go-storage.members"])
+	assert.Equal(t, 6, pos["go-storage.description"])
+	assert.Equal(t, 10, pos["go-storage.role"])
+	assert.Equal(t, 11, pos["go-storage.links.docs"])
+	assert.Equal(t, 13, pos["go-service"])
+	assert.Equal(t, 14, pos["go-service.parent"])
+	assert.Equal(t, 14, pos["go-service.parent.name"])
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GeneratedHeader marks files generated from teams.toml.
+const GeneratedHeader = "This file is generated from teams.toml, please don't edit it directly."
+
+// projectMembers returns the highest role of every member of teams which
+// apply to the project, ordered by role and login.
+func projectMembers(project string, teams Teams, minRole Role) (logins []string, roles map[string]Role) {
+	roles = make(map[string]Role)
+	for _, t := range teams {
+		if t.Project != "" && t.Project != project {
+			continue
+		}
+		if t.Role.rank() < minRole.rank() {
+			continue
+		}
+		for _, v := range t.Members {
+			if r, ok := roles[v]; !ok || t.Role.rank() > r.rank() {
+				roles[v] = t.Role
+			}
+		}
+	}
+
+	for v := range roles {
+		logins = append(logins, v)
+	}
+	sort.Slice(logins, func(i, j int) bool {
+		ri, rj := roles[logins[i]].rank(), roles[logins[j]].rank()
+		if ri != rj {
+			return ri > rj
+		}
+		return logins[i] < logins[j]
+	})
+	return logins, roles
+}
+
+// RenderMaintainers renders MAINTAINERS.md of the repo, which lists members
+// of all its projects with reviewer role and above.
+func RenderMaintainers(repo Repo, teams Teams, users Users) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "<!-- %s -->\n\n", GeneratedHeader)
+	fmt.Fprintf(b, "# Maintainers\n")
+
+	projects := append([]string{}, repo.Project...)
+	sort.Strings(projects)
+	for _, project := range projects {
+		logins, roles := projectMembers(project, teams, RoleReviewer)
+
+		fmt.Fprintf(b, "\n## %s\n\n", project)
+		fmt.Fprintf(b, "| Name | GitHub | Role |\n")
+		fmt.Fprintf(b, "| ---- | ---- | ---- |\n")
+		for _, v := range logins {
+			fmt.Fprintf(b, "| %s | [@%s](https://github.com/%s) | %s |\n",
+				users.DisplayName(v), v, v, roles[v])
+		}
+
+		// Teams without project like pmc apply to all projects.
+		emeritus := make([]string, 0)
+		for _, t := range teams {
+			if t.Project != "" && t.Project != project {
+				continue
+			}
+			if t.Role.rank() < RoleReviewer.rank() {
+				continue
+			}
+			for _, v := range t.Emeritus {
+				if _, ok := roles[v]; !ok {
+					emeritus = mergeList(emeritus, []string{v})
+				}
+			}
+		}
+		if len(emeritus) == 0 {
+			continue
+		}
+		sort.Strings(emeritus)
+		fmt.Fprintf(b, "\n### Emeritus\n\n")
+		for _, v := range emeritus {
+			fmt.Fprintf(b, "- %s ([@%s](https://github.com/%s))\n", users.DisplayName(v), v, v)
+		}
+	}
+	return b.String()
+}
+
+// RenderCodeowners renders CODEOWNERS of the repo, all teams with committer
+// role and above of its projects own the whole repo.
+func RenderCodeowners(owner string, repo Repo, teams Teams) string {
+	projects := make(map[string]struct{})
+	for _, v := range repo.Project {
+		projects[v] = struct{}{}
+	}
+
+	names := make([]string, 0)
+	for name, t := range teams {
+		if _, ok := projects[t.Project]; !ok {
+			continue
+		}
+		if t.Role.rank() < RoleCommitter.rank() || len(t.Members) == 0 {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := teams[names[i]].Role.rank(), teams[names[j]].Role.rank()
+		if ri != rj {
+			return ri > rj
+		}
+		return names[i] < names[j]
+	})
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "# %s\n", GeneratedHeader)
+	if len(names) == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(b, "\n*")
+	for _, v := range names {
+		fmt.Fprintf(b, " @%s/%s", owner, v)
+	}
+	fmt.Fprintf(b, "\n")
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var maintainersTeams = Teams{
+	"pmc":                   {Role: RoleAdmin, Members: []string{"xuanwo"}, Emeritus: []string{"bill"}},
+	"go-storage-maintainer": {Project: "go-storage", Role: RoleMaintainer, Members: []string{"alice"}, Emeritus: []string{"dave"}},
+	"go-storage-committer":  {Project: "go-storage", Role: RoleCommitter, Members: []string{"alice", "bob"}},
+	"go-storage-reviewer":   {Project: "go-storage", Role: RoleReviewer, Members: []string{"carol"}},
+	"go-service-maintainer": {Project: "go-service", Role: RoleMaintainer, Members: []string{"eve"}},
+	"go-storage-empty":      {Project: "go-storage", Role: RoleCommitter},
+}
+
+func TestRenderMaintainers(t *testing.T) {
+	users := Users{
+		"alice": {Name: "Alice"},
+		"dave":  {Name: "Dave"},
+	}
+	repo := Repo{Name: "go-storage", Project: []string{"go-storage"}}
+
+	assert.Equal(t, `<!-- `+GeneratedHeader+` -->
+
+# Maintainers
+
+## go-storage
+
+| Name | GitHub | Role |
+| ---- | ---- | ---- |
+| xuanwo | [@xuanwo](https://github.com/xuanwo) | admin |
+| Alice | [@alice](https://github.com/alice) | maintainer |
+| bob | [@bob](https://github.com/bob) | committer |
+| carol | [@carol](https://github.com/carol) | reviewer |
+
+### Emeritus
+
+- bill ([@bill](https://github.com/bill))
+- Dave ([@dave](https://github.com/dave))
+`, RenderMaintainers(repo, maintainersTeams, users))
+}
+
+func TestRenderCodeowners(t *testing.T) {
+	repo := Repo{Name: "go-service-s3", Project: []string{"go-storage", "go-service"}}
+
+	assert.Equal(t, `# `+GeneratedHeader+`
+
+* @beyondstorage/go-service-maintainer @beyondstorage/go-storage-maintainer @beyondstorage/go-storage-committer
+`, RenderCodeowners("beyondstorage", repo, maintainersTeams))
+
+	assert.Equal(t, "# "+GeneratedHeader+"\n",
+		RenderCodeowners("beyondstorage", Repo{Project: []string{"unknown"}}, maintainersTeams))
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// usesRegexp matches a `uses:` line in workflows, the groups are prefix,
+// quote, action, ref and trailing comment.
+var usesRegexp = regexp.MustCompile(`^(\s*(?:-\s+)?uses:\s*)(["']?)([^@\s"'#]+)@([^\s"'#]+)["']?(\s*#.*)?$`)
+
+// ActionRef is a reference to a remote action in `uses:` of workflows.
+type ActionRef struct {
+	// Action is like "owner/repo" or "owner/repo/path".
+	Action string
+	// Ref is a tag, branch or commit sha.
+	Ref string
+	// Tag is the tag in the trailing comment of pinned refs.
+	Tag string
+}
+
+// Repo returns the owner and repo of the action.
+func (r ActionRef) Repo() (owner, repo string) {
+	s := strings.SplitN(r.Action, "/", 3)
+	if len(s) < 2 {
+		return s[0], ""
+	}
+	return s[0], s[1]
+}
+
+// Pinned checks whether the ref is a commit sha.
+func (r ActionRef) Pinned() bool {
+	return commitSHARegexp.MatchString(r.Ref)
+}
+
+// Bumpable checks whether the ref could be bumped, which is a pinned ref
+// with a tag in comment or a version tag.
+func (r ActionRef) Bumpable() bool {
+	if r.Pinned() {
+		return r.Tag != ""
+	}
+	_, _, ok := parseVersion(r.Ref)
+	return ok
+}
+
+func (r ActionRef) String() string {
+	if r.Tag == "" {
+		return fmt.Sprintf("%s@%s", r.Action, r.Ref)
+	}
+	return fmt.Sprintf("%s@%s # %s", r.Action, r.Ref, r.Tag)
+}
+
+// RewriteActionRefs calls fn for every remote action used in the workflow
+// and replaces it with the returned ref. Local actions and docker images are
+// kept as is.
+//
+// Only `uses:` lines are rewritten, so comments and formatting of the
+// workflow are kept.
+func RewriteActionRefs(data []byte, fn func(ActionRef) (ActionRef, error)) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		m := usesRegexp.FindStringSubmatch(line)
+		if m == nil || strings.HasPrefix(m[3], ".") || strings.HasPrefix(m[3], "docker:") {
+			continue
+		}
+
+		ref := ActionRef{
+			Action: m[3],
+			Ref:    m[4],
+		}
+		if ref.Pinned() {
+			ref.Tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(m[5]), "#"))
+		}
+
+		x, err := fn(ref)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if x == ref {
+			continue
+		}
+
+		lines[i] = fmt.Sprintf("%s%s%s@%s%s", m[1], m[2], x.Action, x.Ref, m[2])
+		if x.Tag != "" {
+			lines[i] += " # " + x.Tag
+		}
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// parseVersion parses tags like "v1", "v1.2" and "v1.2.3", ok is false for
+// tags which are not versions.
+func parseVersion(tag string) (v [3]int, parts int, ok bool) {
+	s := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	if len(s) > 3 {
+		return v, 0, false
+	}
+	for i, x := range s {
+		n, err := strconv.Atoi(x)
+		if err != nil || n < 0 {
+			return v, 0, false
+		}
+		v[i] = n
+	}
+	return v, len(s), true
+}
+
+// BumpActionRef moves the ref to the latest tag, tags maps tag names of the
+// action repo to commit shas. Pinned refs are moved to the commit of the
+// latest tag after the tag in their comment, and version tags are moved to
+// the latest tag directly.
+//
+// ok is false if the latest tag of a pinned ref is not in tags.
+func BumpActionRef(r ActionRef, tags map[string]string, major bool) (x ActionRef, ok bool) {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+
+	if !r.Pinned() {
+		return ActionRef{Action: r.Action, Ref: LatestTag(names, r.Ref, major)}, true
+	}
+	latest := LatestTag(names, r.Tag, major)
+	sha, ok := tags[latest]
+	if !ok {
+		return r, false
+	}
+	return ActionRef{Action: r.Action, Ref: sha, Tag: latest}, true
+}
+
+// LatestTag returns the latest version tag after current. Tags with less
+// parts than current like "v2" for "v2.1.0" are ignored, and the major
+// version is kept unless major is true.
+//
+// current itself is returned if there is no newer tag.
+func LatestTag(tags []string, current string, major bool) string {
+	cv, cp, ok := parseVersion(current)
+	if !ok {
+		return current
+	}
+
+	latest, lv := current, cv
+	for _, tag := range tags {
+		v, p, ok := parseVersion(tag)
+		if !ok || p != cp || (!major && v[0] != cv[0]) {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			if v[i] != lv[i] {
+				if v[i] > lv[i] {
+					latest, lv = tag, v
+				}
+				break
+			}
+		}
+	}
+	return latest
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	checkoutSHA = "5a4ac9002d0be2fb38bd78e4b4dbde5606d7042f"
+	lintSHA     = "537aa1903e5d359d0b27dbc19ddd22c5087f3fbc"
+)
+
+func TestRewriteActionRefs(t *testing.T) {
+	data := []byte(`jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v2
+      - name: Lint
+        uses: "golangci/golangci-lint-action@` + lintSHA + `" # v2.5.1
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3.8
+      # uses: actions/setup-go@v2
+`)
+
+	refs := make([]string, 0)
+	pinned, err := RewriteActionRefs(data, func(r ActionRef) (ActionRef, error) {
+		refs = append(refs, r.String())
+		if r.Pinned() {
+			return r, nil
+		}
+		return ActionRef{Action: r.Action, Ref: checkoutSHA, Tag: r.Ref}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"actions/checkout@v2",
+		"golangci/golangci-lint-action@" + lintSHA + " # v2.5.1",
+	}, refs)
+	assert.Equal(t, `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@`+checkoutSHA+` # v2
+      - name: Lint
+        uses: "golangci/golangci-lint-action@`+lintSHA+`" # v2.5.1
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3.8
+      # uses: actions/setup-go@v2
+`, string(pinned))
+
+	_, err = RewriteActionRefs(data, func(r ActionRef) (ActionRef, error) {
+		return r, fmt.Errorf("not found")
+	})
+	assert.EqualError(t, err, "line 4: not found")
+}
+
+func TestActionRef_Repo(t *testing.T) {
+	owner, repo := ActionRef{Action: "github/codeql-action/init"}.Repo()
+	assert.Equal(t, "github", owner)
+	assert.Equal(t, "codeql-action", repo)
+}
+
+func TestBumpActionRef(t *testing.T) {
+	tags := map[string]string{
+		"v2.5.1":  lintSHA,
+		"v2.10.0": checkoutSHA,
+		"v3.0.0":  "0000000000000000000000000000000000000000",
+	}
+
+	// Unpinned templates keep the tag ref.
+	r := ActionRef{Action: "actions/checkout", Ref: "v2.5.1"}
+	assert.True(t, r.Bumpable())
+	x, ok := BumpActionRef(r, tags, false)
+	assert.True(t, ok)
+	assert.Equal(t, ActionRef{Action: "actions/checkout", Ref: "v2.10.0"}, x)
+
+	r = ActionRef{Action: "actions/checkout", Ref: lintSHA, Tag: "v2.5.1"}
+	assert.True(t, r.Bumpable())
+	x, ok = BumpActionRef(r, tags, false)
+	assert.True(t, ok)
+	assert.Equal(t, ActionRef{Action: "actions/checkout", Ref: checkoutSHA, Tag: "v2.10.0"}, x)
+
+	// The tag in comment has been deleted.
+	r = ActionRef{Action: "actions/checkout", Ref: lintSHA, Tag: "v2.4.0"}
+	_, ok = BumpActionRef(r, map[string]string{"v1.0.0": lintSHA}, false)
+	assert.False(t, ok)
+
+	assert.False(t, ActionRef{Action: "actions/checkout", Ref: "main"}.Bumpable())
+	assert.False(t, ActionRef{Action: "actions/checkout", Ref: lintSHA}.Bumpable())
+}
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"v3", "v3.0.0", "v2", "v2.5.1", "v2.10.0", "v2.9.3", "latest"}
+
+	assert.Equal(t, "v2.10.0", LatestTag(tags, "v2.5.1", false))
+	assert.Equal(t, "v3.0.0", LatestTag(tags, "v2.5.1", true))
+	assert.Equal(t, "v2", LatestTag(tags, "v2", false))
+	assert.Equal(t, "v3", LatestTag(tags, "v2", true))
+	assert.Equal(t, "main", LatestTag(tags, "main", true))
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PromotionThreshold is the minimum activity to be promoted into a role,
+// zero fields are not checked.
+type PromotionThreshold struct {
+	MergedPRs int `toml:"merged_prs"`
+	Reviews   int `toml:"reviews"`
+	Triages   int `toml:"triages"`
+	// Disabled stops suggesting promotions into the role.
+	Disabled bool `toml:"disabled"`
+}
+
+func (t PromotionThreshold) IsZero() bool {
+	return t.MergedPRs == 0 && t.Reviews == 0 && t.Triages == 0
+}
+
+func (t PromotionThreshold) Reached(a *Activity) bool {
+	return len(a.MergedPRs) >= t.MergedPRs &&
+		len(a.Reviews) >= t.Reviews &&
+		len(a.Triages) >= t.Triages
+}
+
+// PromotionPolicy decides who will be suggested to be promoted.
+//
+// Roles without threshold use the default one, and roles with disabled
+// threshold are skipped. Admin is never suggested.
+type PromotionPolicy struct {
+	Window     Duration           `toml:"window"`
+	Reviewer   PromotionThreshold `toml:"reviewer"`
+	Committer  PromotionThreshold `toml:"committer"`
+	Maintainer PromotionThreshold `toml:"maintainer"`
+}
+
+// DefaultPromotionPolicy is used for fields not set in config.
+var DefaultPromotionPolicy = PromotionPolicy{
+	Window:     Duration(180 * 24 * time.Hour),
+	Reviewer:   PromotionThreshold{MergedPRs: 5, Reviews: 5},
+	Committer:  PromotionThreshold{MergedPRs: 10, Reviews: 20, Triages: 10},
+	Maintainer: PromotionThreshold{MergedPRs: 30, Reviews: 50, Triages: 20},
+}
+
+func (p PromotionPolicy) GetWindow() Duration {
+	if p.Window == 0 {
+		return DefaultPromotionPolicy.Window
+	}
+	return p.Window
+}
+
+// GetThreshold returns the threshold to be promoted into the role, roles
+// without threshold use the default one.
+func (p PromotionPolicy) GetThreshold(r Role) PromotionThreshold {
+	if t := p.threshold(r); t != (PromotionThreshold{}) {
+		return t
+	}
+	return DefaultPromotionPolicy.threshold(r)
+}
+
+func (p PromotionPolicy) threshold(r Role) PromotionThreshold {
+	switch r {
+	case RoleReviewer:
+		return p.Reviewer
+	case RoleCommitter:
+		return p.Committer
+	case RoleMaintainer:
+		return p.Maintainer
+	default:
+		return PromotionThreshold{}
+	}
+}
+
+// Promotion is a suggestion to promote a user in a project.
+type Promotion struct {
+	Login   string
+	Project string
+	From    Role
+	To      Role
+	// Team is the team the user should join, it may not exist yet.
+	Team     string
+	Activity *Activity
+}
+
+func (p Promotion) String() string {
+	return fmt.Sprintf("@%s: %s -> %s in %s (team %s)", p.Login, p.From, p.To, p.Project, p.Team)
+}
+
+// SuggestPromotions checks activities grouped by project and suggests
+// promoting users to the next role if the threshold has been reached.
+func SuggestPromotions(teams Teams, activities map[string]Activities, policy PromotionPolicy) []Promotion {
+	ps := make([]Promotion, 0)
+	for project, as := range activities {
+		for login, a := range as {
+			from := teams.RoleOf(project, login)
+			to, ok := from.Next()
+			if !ok {
+				continue
+			}
+			t := policy.GetThreshold(to)
+			if t.Disabled || t.IsZero() || !t.Reached(a) {
+				continue
+			}
+			ps = append(ps, Promotion{
+				Login:    login,
+				Project:  project,
+				From:     from,
+				To:       to,
+				Team:     teams.TeamOf(project, to),
+				Activity: a,
+			})
+		}
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Project != ps[j].Project {
+			return ps[i].Project < ps[j].Project
+		}
+		return ps[i].Login < ps[j].Login
+	})
+	return ps
+}
+
+// PromotionPatch renders the teams that promotions touch with new members
+// added, which could be pasted into teams.toml directly.
+func PromotionPatch(teams Teams, ps []Promotion) (string, error) {
+	patch := make(Teams)
+	for _, p := range ps {
+		t, ok := patch[p.Team]
+		if !ok {
+			t, ok = teams[p.Team]
+			if !ok {
+				t = Team{Project: p.Project, Role: p.To}
+			}
+			t.Members = append([]string{}, t.Members...)
+		}
+		t.Members = append(t.Members, p.Login)
+		patch[p.Team] = t
+	}
+
+	b := &strings.Builder{}
+	err := toml.NewEncoder(b).Encode(patch)
+	if err != nil {
+		return "", fmt.Errorf("toml marshal: %v", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestPromotions(t *testing.T) {
+	teams, err := LoadTeams("testdata/teams.toml")
+	if err != nil {
+		t.Fatal("load teams", err)
+	}
+
+	activities := map[string]Activities{
+		"go-storage": {
+			"alice": {
+				MergedPRs: []string{"pr1", "pr2"},
+				Reviews:   []string{"review1", "review2"},
+			},
+			"bob": {
+				MergedPRs: []string{"pr3"},
+			},
+			"test-user": {
+				MergedPRs: []string{"pr4", "pr5"},
+				Reviews:   []string{"review3", "review4"},
+				Triages:   []string{"issue1"},
+			},
+		},
+	}
+	policy := PromotionPolicy{
+		Reviewer:   PromotionThreshold{MergedPRs: 2, Reviews: 2},
+		Committer:  PromotionThreshold{Disabled: true},
+		Maintainer: PromotionThreshold{MergedPRs: 1},
+	}
+
+	ps := SuggestPromotions(teams, activities, policy)
+	assert.Len(t, ps, 1)
+	assert.Equal(t, "alice", ps[0].Login)
+	assert.Equal(t, RoleContributor, ps[0].From)
+	assert.Equal(t, RoleReviewer, ps[0].To)
+	assert.Equal(t, "go-storage-reviewer", ps[0].Team)
+
+	policy.Reviewer.Disabled = true
+	assert.Empty(t, SuggestPromotions(teams, activities, policy))
+
+	patch, err := PromotionPatch(teams, ps)
+	if err != nil {
+		t.Fatal("promotion patch", err)
+	}
+	assert.Equal(t, `[go-storage-reviewer]
+  project = "go-storage"
+  role = "reviewer"
+  members = ["alice"]
+`, patch)
+}
+
+func TestPromotionPolicy_Defaults(t *testing.T) {
+	policy := PromotionPolicy{
+		Window:   Duration(30 * 24 * time.Hour),
+		Reviewer: PromotionThreshold{MergedPRs: 2},
+	}
+
+	assert.Equal(t, Duration(30*24*time.Hour), policy.GetWindow())
+	assert.Equal(t, PromotionThreshold{MergedPRs: 2}, policy.GetThreshold(RoleReviewer))
+	assert.Equal(t, DefaultPromotionPolicy.Committer, policy.GetThreshold(RoleCommitter))
+	policy.Maintainer.Disabled = true
+	assert.True(t, policy.GetThreshold(RoleMaintainer).Disabled)
+	assert.Equal(t, DefaultPromotionPolicy.Window, PromotionPolicy{}.GetWindow())
+}
+
+func TestTeams_RoleOf(t *testing.T) {
+	teams := Teams{
+		"pmc":                   {Role: RoleAdmin, Members: []string{"admin"}},
+		"go-storage-maintainer": {Project: "go-storage", Role: RoleMaintainer, Members: []string{"alice"}},
+		"go-storage-committer":  {Project: "go-storage", Role: RoleCommitter, Members: []string{"alice", "bob"}},
+	}
+
+	assert.Equal(t, RoleAdmin, teams.RoleOf("go-storage", "admin"))
+	assert.Equal(t, RoleMaintainer, teams.RoleOf("go-storage", "alice"))
+	assert.Equal(t, RoleCommitter, teams.RoleOf("go-storage", "bob"))
+	assert.Equal(t, RoleContributor, teams.RoleOf("go-service-s3", "bob"))
+	assert.Equal(t, "go-storage-committer", teams.TeamOf("go-storage", RoleCommitter))
+}
@@ -2,10 +2,12 @@ package model
 
 import (
 	"fmt"
-	"github.com/gobwas/glob"
 	"io/ioutil"
+	"sort"
+	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/gobwas/glob"
 )
 
 type Repos map[string]Repo
@@ -22,81 +24,330 @@ func (r Repos) ParsedProjects() map[string][]string {
 }
 
 type Repo struct {
-	Name    string
-	Project []string
-	Action  RepoAction
+	Name    string     `toml:"-"`
+	Project []string   `toml:"project"`
+	Action  RepoAction `toml:"action"`
+
+	// Secrets and Variables are names of github actions secrets and
+	// variables of the repo, values are never stored in repos.toml.
+	Secrets   []string `toml:"secrets,omitempty"`
+	Variables []string `toml:"variables,omitempty"`
 
-	// We use from to record where the repo config from.
-	// If from is shorter than name, we can overwrite the data safely.
+	// Stale overrides the stale policy of community.toml for the repo.
+	Stale *StalePolicy `toml:"stale,omitempty"`
+
+	// Inherit will merge this config on top of the next matched pattern
+	// instead of replacing it. Lists are appended, entries prefixed with
+	// "-" remove the inherited entry.
+	Inherit bool `toml:"inherit,omitempty"`
+
+	// We use from to record which pattern the repo config comes from.
 	from string
 }
 
+// RepoAction lists workflows of the repo by name without extension, entries
+// could be glob patterns like "release-*".
 type RepoAction struct {
-	Required []string
-	Allowed  []string
+	Required []string `toml:"required"`
+	Allowed  []string `toml:"allowed"`
+
+	required []glob.Glob
+	allowed  []glob.Glob
+}
+
+func (ra *RepoAction) parse() {
+	ra.required = compileActionPatterns(ra.Required)
+	ra.allowed = compileActionPatterns(ra.Allowed)
+}
 
-	required map[string]struct{}
-	allowed  map[string]struct{}
+// compileActionPatterns compiles patterns, invalid patterns are matched
+// literally and reported by Lint.
+func compileActionPatterns(patterns []string) []glob.Glob {
+	gs := make([]glob.Glob, 0, len(patterns))
+	for _, v := range patterns {
+		g, err := glob.Compile(v)
+		if err != nil {
+			g = glob.MustCompile(glob.QuoteMeta(v))
+		}
+		gs = append(gs, g)
+	}
+	return gs
+}
+
+func matchActionPatterns(gs []glob.Glob, name string) bool {
+	for _, g := range gs {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
 }
 
 func (ra *RepoAction) IsRequired(name string) bool {
-	_, ok := ra.required[name]
-	return ok
+	return matchActionPatterns(ra.required, name)
 }
 
 func (ra *RepoAction) IsAllowed(name string) bool {
-	_, ok := ra.allowed[name]
-	return ok
+	return matchActionPatterns(ra.allowed, name)
+}
+
+// IsRemovable returns true if the workflow is neither required nor allowed.
+// Workflows matching a required pattern are kept even without a local
+// action to sync them with.
+func (ra *RepoAction) IsRemovable(name string) bool {
+	return !ra.IsRequired(name) && !ra.IsAllowed(name)
+}
+
+// RequiredActions expands required patterns with available local actions.
+// Required names without glob syntax are always returned, so that missing
+// local actions will be reported.
+func (ra *RepoAction) RequiredActions(available []string) []string {
+	names := make([]string, 0)
+	for _, v := range ra.Required {
+		if !strings.ContainsAny(v, `*?[{\`) {
+			names = mergeList(names, []string{v})
+		}
+	}
+	for _, v := range available {
+		if ra.IsRequired(v) {
+			names = mergeList(names, []string{v})
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
-// LoadRepos will following the neatest overwrite rule.
+// RepoMatch is a pattern in repos.toml which matches a repo.
+type RepoMatch struct {
+	Pattern string
+	Repo    Repo
+
+	order  int
+	prefix string
+	glob   glob.Glob
+}
+
+// Exact returns true if the pattern doesn't contain any glob syntax.
+func (m RepoMatch) Exact() bool {
+	return m.prefix == m.Pattern
+}
+
+// LoadRepos will follow the nearest overwrite rule, see ExplainRepo for
+// the precedence between patterns.
 func LoadRepos(path string, githubRepos []string) (Repos, error) {
+	patterns, err := loadRepoPatterns(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parsed into repos
+	repos := make(Repos)
+	for _, repoName := range githubRepos {
+		ms := matchRepoPatterns(patterns, repoName)
+		if len(ms) == 0 {
+			continue
+		}
+		repos[repoName] = resolveRepo(ms, repoName)
+	}
+
+	return repos, nil
+}
+
+// ExplainRepo returns all patterns that match the repo ordered by
+// precedence, and the config the repo resolves to.
+//
+// Patterns are ordered by:
+//
+//   - exact names before glob patterns
+//   - longer literal prefix (the part before the first glob syntax) first
+//   - later declarations in repos.toml first
+func ExplainRepo(path, name string) (ms []RepoMatch, repo Repo, err error) {
+	patterns, err := loadRepoPatterns(path)
+	if err != nil {
+		return nil, Repo{}, err
+	}
+
+	ms = matchRepoPatterns(patterns, name)
+	if len(ms) == 0 {
+		return nil, Repo{}, fmt.Errorf("repo %s matches no patterns", name)
+	}
+	return ms, resolveRepo(ms, name), nil
+}
+
+func loadRepoPatterns(path string) ([]RepoMatch, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read file %s: %v", path, err)
 	}
 
 	var x Repos
-	err = toml.Unmarshal(data, &x)
+	md, err := toml.Decode(string(data), &x)
 	if err != nil {
 		return nil, fmt.Errorf("toml unmarshal: %v", err)
 	}
 
-	// Parsed into repos
-	repos := make(Repos)
-	for patternName, repo := range x {
-		repo.Action.required = make(map[string]struct{})
-		for _, v := range repo.Action.Required {
-			repo.Action.required[v] = struct{}{}
+	patterns := make([]RepoMatch, 0, len(x))
+	for _, k := range md.Keys() {
+		if len(k) != 1 {
+			continue
 		}
+		patternName := k[0]
+		repo := x[patternName]
 
-		repo.Action.allowed = make(map[string]struct{})
-		for _, v := range repo.Action.Allowed {
-			repo.Action.allowed[v] = struct{}{}
+		g, err := glob.Compile(patternName)
+		if err != nil {
+			return nil, fmt.Errorf("compile pattern %s: %v", patternName, err)
 		}
 
-		g := glob.MustCompile(patternName)
-		for _, repoName := range githubRepos {
-			if !g.Match(repoName) {
-				continue
-			}
-			// If the repo is not recorded, we can save directly.
-			if _, ok := repos[repoName]; !ok {
-				repo.from = patternName
-				repo.Name = repoName
-				repos[repoName] = repo
-				continue
-			}
-			// If repos from is shorted than current matched repo, we need to update it.
-			if len(repos[repoName].from) < len(patternName) {
-				repo.from = patternName
-				repo.Name = repoName
-				repos[repoName] = repo
-				continue
+		prefix := patternName
+		if idx := strings.IndexAny(patternName, `*?[{\`); idx >= 0 {
+			prefix = patternName[:idx]
+		}
+
+		patterns = append(patterns, RepoMatch{
+			Pattern: patternName,
+			Repo:    repo,
+			order:   len(patterns),
+			prefix:  prefix,
+			glob:    g,
+		})
+	}
+	return patterns, nil
+}
+
+func matchRepoPatterns(patterns []RepoMatch, name string) []RepoMatch {
+	ms := make([]RepoMatch, 0)
+	for _, p := range patterns {
+		if p.glob.Match(name) {
+			ms = append(ms, p)
+		}
+	}
+
+	sort.SliceStable(ms, func(i, j int) bool {
+		if ms[i].Exact() != ms[j].Exact() {
+			return ms[i].Exact()
+		}
+		if len(ms[i].prefix) != len(ms[j].prefix) {
+			return len(ms[i].prefix) > len(ms[j].prefix)
+		}
+		return ms[i].order > ms[j].order
+	})
+	return ms
+}
+
+// resolveRepo builds the repo config from matches ordered by precedence.
+//
+// The winner is used as is unless it inherits, in which case it's merged on
+// top of the next match, and so on.
+func resolveRepo(ms []RepoMatch, name string) Repo {
+	n := 0
+	for n < len(ms)-1 && ms[n].Repo.Inherit {
+		n++
+	}
+
+	repo := Repo{
+		Project: mergeList(nil, ms[n].Repo.Project),
+		Action: RepoAction{
+			Required: mergeList(nil, ms[n].Repo.Action.Required),
+			Allowed:  mergeList(nil, ms[n].Repo.Action.Allowed),
+		},
+		Secrets:   mergeList(nil, ms[n].Repo.Secrets),
+		Variables: mergeList(nil, ms[n].Repo.Variables),
+		Stale:     inheritStalePolicy(nil, ms[n].Repo.Stale),
+	}
+	for i := n - 1; i >= 0; i-- {
+		repo.Project = mergeList(repo.Project, ms[i].Repo.Project)
+		repo.Action.Required = mergeList(repo.Action.Required, ms[i].Repo.Action.Required)
+		repo.Action.Allowed = mergeList(repo.Action.Allowed, ms[i].Repo.Action.Allowed)
+		repo.Secrets = mergeList(repo.Secrets, ms[i].Repo.Secrets)
+		repo.Variables = mergeList(repo.Variables, ms[i].Repo.Variables)
+		repo.Stale = inheritStalePolicy(repo.Stale, ms[i].Repo.Stale)
+	}
+
+	repo.Action.parse()
+	repo.from = ms[0].Pattern
+	repo.Name = name
+	return repo
+}
+
+// mergeList appends values to base, values prefixed with "-" will remove
+// the entry from base instead.
+func mergeList(base, values []string) []string {
+	res := make([]string, 0, len(base)+len(values))
+	res = append(res, base...)
+
+	for _, v := range values {
+		if strings.HasPrefix(v, "-") {
+			v = strings.TrimPrefix(v, "-")
+			for i := 0; i < len(res); i++ {
+				if res[i] == v {
+					res = append(res[:i], res[i+1:]...)
+					i--
+				}
 			}
 			continue
 		}
+
+		exist := false
+		for _, r := range res {
+			if r == v {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			res = append(res, v)
+		}
 	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
 
-	return repos, nil
+// RepoFilter decides which github repos the community tools will work on.
+//
+// The zero value includes public repos and skips archived ones.
+type RepoFilter struct {
+	IncludeArchived  bool     `toml:"include_archived"`
+	IncludePrivate   bool     `toml:"include_private"`
+	ExcludeForks     bool     `toml:"exclude_forks"`
+	ExcludeTemplates bool     `toml:"exclude_templates"`
+	Topics           []string `toml:"topics"`
+	ExcludeTopics    []string `toml:"exclude_topics"`
+}
+
+// Match checks whether a repo with given attributes passes the filter.
+func (f RepoFilter) Match(archived, private, fork, template bool, topics []string) bool {
+	if archived && !f.IncludeArchived {
+		return false
+	}
+	if private && !f.IncludePrivate {
+		return false
+	}
+	if fork && f.ExcludeForks {
+		return false
+	}
+	if template && f.ExcludeTemplates {
+		return false
+	}
+
+	has := make(map[string]struct{}, len(topics))
+	for _, v := range topics {
+		has[v] = struct{}{}
+	}
+	for _, v := range f.ExcludeTopics {
+		if _, ok := has[v]; ok {
+			return false
+		}
+	}
+	if len(f.Topics) == 0 {
+		return true
+	}
+	for _, v := range f.Topics {
+		if _, ok := has[v]; ok {
+			return true
+		}
+	}
+	return false
 }
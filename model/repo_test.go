@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -26,3 +27,107 @@ func TestRepos_ParsedProjects(t *testing.T) {
 
 	assert.ElementsMatch(t, []string{"abc"}, p["root"])
 }
+
+func TestLoadRepos_Precedence(t *testing.T) {
+	x, err := LoadRepos("testdata/repos-precedence.toml", []string{"go-service-s3", "go-service-x3", "go-storage-s3"})
+	if err != nil {
+		t.Fatal("load repos", err)
+	}
+
+	// exact name wins
+	assert.ElementsMatch(t, []string{"go-service-s3"}, x["go-service-s3"].Project)
+	// longer literal prefix wins, then later declaration wins
+	assert.ElementsMatch(t, []string{"go-service-s3-like"}, x["go-service-x3"].Project)
+	// "go-*-s3" and "go-*" both have literal prefix "go-"
+	assert.ElementsMatch(t, []string{"go-s3"}, x["go-storage-s3"].Project)
+}
+
+func TestExplainRepo(t *testing.T) {
+	ms, repo, err := ExplainRepo("testdata/repos-precedence.toml", "go-service-s3")
+	if err != nil {
+		t.Fatal("explain repo", err)
+	}
+
+	patterns := make([]string, 0, len(ms))
+	for _, m := range ms {
+		patterns = append(patterns, m.Pattern)
+	}
+	assert.Equal(t, []string{"go-service-s3", "go-service-?3", "go-service-*", "go-*-s3", "go-*"}, patterns)
+	assert.Equal(t, "go-service-s3", repo.Name)
+	assert.ElementsMatch(t, []string{"go-service-s3"}, repo.Project)
+
+	_, _, err = ExplainRepo("testdata/repos-precedence.toml", "dm")
+	assert.Error(t, err)
+}
+
+func TestLoadRepos_Inherit(t *testing.T) {
+	x, err := LoadRepos("testdata/repos-inherit.toml", []string{"go-service-s3", "go-service-gcs", "go-service-oss"})
+	if err != nil {
+		t.Fatal("load repos", err)
+	}
+
+	repo := x["go-service-oss"]
+	assert.Equal(t, []string{"root", "go-service"}, repo.Project)
+	assert.Equal(t, []string{"unit-test", "integration-test"}, repo.Action.Required)
+	assert.Equal(t, []string{"release"}, repo.Action.Allowed)
+	assert.True(t, repo.Action.IsRequired("integration-test"))
+	assert.False(t, repo.Action.IsRequired("build-test"))
+	assert.Equal(t, []string{"CODECOV_TOKEN", "STORAGE_CREDENTIAL"}, repo.Secrets)
+	assert.Equal(t, []string{"STORAGE_ENDPOINT"}, repo.Variables)
+	assert.Equal(t, &StalePolicy{
+		Inactive:     Duration(30 * 24 * time.Hour),
+		ExemptLabels: []string{"-pinned", "needs-design"},
+		ExcludePRs:   boolPtr(true),
+	}, repo.Stale)
+
+	// inherit through multiple patterns
+	repo = x["go-service-s3"]
+	assert.Equal(t, []string{"go-service"}, repo.Project)
+	assert.Equal(t, []string{"STORAGE_CREDENTIAL"}, repo.Secrets)
+	assert.Equal(t, []string{"unit-test", "integration-test"}, repo.Action.Required)
+	assert.Equal(t, Duration(30*24*time.Hour), repo.Stale.Inactive)
+	assert.Equal(t, Duration(14*24*time.Hour), repo.Stale.Grace)
+
+	// patterns without inherit replace the whole config
+	repo = x["go-service-gcs"]
+	assert.Equal(t, []string{"go-service-gcs"}, repo.Project)
+	assert.Empty(t, repo.Action.Required)
+	assert.Nil(t, repo.Stale)
+}
+
+func TestRepoFilter_Match(t *testing.T) {
+	f := RepoFilter{}
+	assert.True(t, f.Match(false, false, true, true, nil))
+	assert.False(t, f.Match(true, false, false, false, nil))
+	assert.False(t, f.Match(false, true, false, false, nil))
+
+	f = RepoFilter{
+		IncludeArchived: true,
+		ExcludeForks:    true,
+		Topics:          []string{"storage"},
+		ExcludeTopics:   []string{"deprecated"},
+	}
+	assert.True(t, f.Match(true, false, false, false, []string{"storage"}))
+	assert.False(t, f.Match(false, false, true, false, []string{"storage"}))
+	assert.False(t, f.Match(false, false, false, false, []string{"go"}))
+	assert.False(t, f.Match(false, false, false, false, []string{"storage", "deprecated"}))
+}
+
+func TestRepoAction_Patterns(t *testing.T) {
+	ra := RepoAction{
+		Required: []string{"unit-test", "release-*"},
+		Allowed:  []string{"docs-[a"},
+	}
+	ra.parse()
+
+	assert.True(t, ra.IsRequired("release-github"))
+	assert.False(t, ra.IsRequired("release"))
+	assert.True(t, ra.IsAllowed("docs-[a"))
+	assert.False(t, ra.IsAllowed("docs-a"))
+	// release-custom has no local action but still matches release-*.
+	assert.False(t, ra.IsRemovable("release-custom"))
+	assert.False(t, ra.IsRemovable("docs-[a"))
+	assert.True(t, ra.IsRemovable("build-test"))
+	assert.Equal(t, []string{"release-docker", "release-github", "unit-test"},
+		ra.RequiredActions([]string{"build-test", "release-github", "release-docker"}))
+}
@@ -13,3 +13,60 @@ const (
 func (r Role) String() string {
 	return string(r)
 }
+
+// roleLadder is the ordered roles a member can climb, from lowest to highest.
+var roleLadder = []Role{
+	RoleContributor,
+	RoleReviewer,
+	RoleCommitter,
+	RoleMaintainer,
+	RoleAdmin,
+}
+
+// Next returns the next role on the ladder, admin is the highest role and
+// has no next role.
+func (r Role) Next() (Role, bool) {
+	i := r.rank()
+	if i < 0 || i+1 >= len(roleLadder) {
+		return "", false
+	}
+	return roleLadder[i+1], true
+}
+
+// rank returns the position of role on the ladder, -1 means unknown role.
+func (r Role) rank() int {
+	for i, v := range roleLadder {
+		if v == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// rolePermissions maps roles to the github repo permission of their teams.
+var rolePermissions = map[Role]string{
+	RoleAdmin:       "admin",
+	RoleMaintainer:  "maintain",
+	RoleCommitter:   "push",
+	RoleReviewer:    "triage",
+	RoleContributor: "pull",
+}
+
+// Permission returns the github repo permission granted to teams of the role.
+func (r Role) Permission() string {
+	return rolePermissions[r]
+}
+
+// repoPermissions is github repo permissions from the lowest.
+var repoPermissions = []string{"pull", "triage", "push", "maintain", "admin"}
+
+// permissionRank returns the position of the permission, unknown permissions
+// like custom roles rank above admin.
+func permissionRank(p string) int {
+	for i, v := range repoPermissions {
+		if v == p {
+			return i
+		}
+	}
+	return len(repoPermissions)
+}
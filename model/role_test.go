@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Next(t *testing.T) {
+	r, ok := RoleContributor.Next()
+	assert.True(t, ok)
+	assert.Equal(t, RoleReviewer, r)
+
+	r, ok = RoleMaintainer.Next()
+	assert.True(t, ok)
+	assert.Equal(t, RoleAdmin, r)
+
+	_, ok = RoleAdmin.Next()
+	assert.False(t, ok)
+
+	_, ok = Role("unknown").Next()
+	assert.False(t, ok)
+}
+
+func TestRole_Permission(t *testing.T) {
+	assert.Equal(t, "maintain", RoleMaintainer.Permission())
+	assert.Equal(t, "pull", RoleContributor.Permission())
+	assert.Less(t, permissionRank("push"), permissionRank("admin"))
+	assert.Greater(t, permissionRank("custom"), permissionRank("admin"))
+}
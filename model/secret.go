@@ -0,0 +1,136 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// SecretValues are values of secrets and variables referenced by repos.toml,
+// keyed by name.
+//
+// They are stored in a local file encrypted with a secret key, see
+// EncryptSecretValues.
+type SecretValues struct {
+	Secrets   map[string]string `toml:"secrets"`
+	Variables map[string]string `toml:"variables"`
+}
+
+// ParseSecretKey parses a base64 encoded 32 bytes key.
+func ParseSecretKey(s string) (*[32]byte, error) {
+	bs, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode secret key: %v", err)
+	}
+	if len(bs) != 32 {
+		return nil, fmt.Errorf("secret key must be 32 bytes, got %d", len(bs))
+	}
+	var key [32]byte
+	copy(key[:], bs)
+	return &key, nil
+}
+
+// EncryptSecretValues encrypts values in toml with key, the result is base64
+// encoded so that it could be committed safely.
+func EncryptSecretValues(data []byte, key *[32]byte) ([]byte, error) {
+	var x SecretValues
+	if _, err := toml.Decode(string(data), &x); err != nil {
+		return nil, fmt.Errorf("toml unmarshal: %v", err)
+	}
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("generate nonce: %v", err)
+	}
+	sealed := secretbox.Seal(nonce[:], data, &nonce, key)
+
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(out, sealed)
+	return out, nil
+}
+
+// DecryptSecretValues decrypts content encrypted by EncryptSecretValues.
+func DecryptSecretValues(data []byte, key *[32]byte) (SecretValues, error) {
+	sealed, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return SecretValues{}, fmt.Errorf("decode secret values: %v", err)
+	}
+	if len(sealed) < 24 {
+		return SecretValues{}, fmt.Errorf("secret values are too short")
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], sealed[:24])
+	plain, ok := secretbox.Open(nil, sealed[24:], &nonce, key)
+	if !ok {
+		return SecretValues{}, fmt.Errorf("decrypt secret values: invalid key")
+	}
+
+	var x SecretValues
+	if _, err := toml.Decode(string(plain), &x); err != nil {
+		return SecretValues{}, fmt.Errorf("toml unmarshal: %v", err)
+	}
+	return x, nil
+}
+
+// LoadSecretValues loads values from the encrypted file.
+func LoadSecretValues(path string, key *[32]byte) (SecretValues, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return SecretValues{}, fmt.Errorf("read file %s: %v", path, err)
+	}
+	return DecryptSecretValues(data, key)
+}
+
+// Missing returns names referenced by repos which have no value.
+func (v SecretValues) Missing(repos Repos) (secrets, variables []string) {
+	ms, mv := make(map[string]struct{}), make(map[string]struct{})
+	for _, repo := range repos {
+		for _, name := range repo.Secrets {
+			if _, ok := v.Secrets[name]; !ok {
+				ms[name] = struct{}{}
+			}
+		}
+		for _, name := range repo.Variables {
+			if _, ok := v.Variables[name]; !ok {
+				mv[name] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(ms), sortedKeys(mv)
+}
+
+// SealSecret encrypts value with the base64 encoded public key of a github
+// repo, the result is base64 encoded as required by github.
+func SealSecret(value, publicKey string) (string, error) {
+	bs, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("decode public key: %v", err)
+	}
+	if len(bs) != 32 {
+		return "", fmt.Errorf("public key must be 32 bytes, got %d", len(bs))
+	}
+	var pk [32]byte
+	copy(pk[:], bs)
+
+	sealed, err := box.SealAnonymous(nil, []byte(value), &pk, rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("seal secret: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestSecretValues(t *testing.T) {
+	var key [32]byte
+	_, err := rand.Read(key[:])
+	assert.NoError(t, err)
+
+	parsed, err := ParseSecretKey(base64.StdEncoding.EncodeToString(key[:]))
+	assert.NoError(t, err)
+	assert.Equal(t, key, *parsed)
+
+	_, err = ParseSecretKey(base64.StdEncoding.EncodeToString(key[:16]))
+	assert.Error(t, err)
+
+	data, err := EncryptSecretValues([]byte(`
+[secrets]
+CODECOV_TOKEN = "token"
+
+[variables]
+STORAGE_ENDPOINT = "https://example.com"
+`), &key)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "token")
+
+	v, err := DecryptSecretValues(data, &key)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"CODECOV_TOKEN": "token"}, v.Secrets)
+	assert.Equal(t, map[string]string{"STORAGE_ENDPOINT": "https://example.com"}, v.Variables)
+
+	var other [32]byte
+	_, err = DecryptSecretValues(data, &other)
+	assert.Error(t, err)
+
+	secrets, variables := v.Missing(Repos{
+		"go-service-s3": {
+			Secrets:   []string{"CODECOV_TOKEN", "STORAGE_CREDENTIAL"},
+			Variables: []string{"STORAGE_ENDPOINT"},
+		},
+		"go-service-gcs": {
+			Secrets:   []string{"STORAGE_CREDENTIAL"},
+			Variables: []string{"STORAGE_BUCKET"},
+		},
+	})
+	assert.Equal(t, []string{"STORAGE_CREDENTIAL"}, secrets)
+	assert.Equal(t, []string{"STORAGE_BUCKET"}, variables)
+}
+
+func TestSealSecret(t *testing.T) {
+	pk, sk, err := box.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	sealed, err := SealSecret("token", base64.StdEncoding.EncodeToString(pk[:]))
+	assert.NoError(t, err)
+
+	bs, err := base64.StdEncoding.DecodeString(sealed)
+	assert.NoError(t, err)
+	opened, ok := box.OpenAnonymous(nil, bs, pk, sk)
+	assert.True(t, ok)
+	assert.Equal(t, "token", string(opened))
+
+	_, err = SealSecret("token", "invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Security checks of a repo.
+const (
+	CheckSecretScanning      = "secret-scanning"
+	CheckDependabotUpdates   = "dependabot-updates"
+	CheckVulnerabilityAlerts = "vulnerability-alerts"
+	CheckBranchProtection    = "branch-protection"
+	CheckWorkflowPermissions = "workflow-permissions"
+	CheckForkPRApproval      = "fork-pr-approval"
+	CheckSecurityPolicy      = "security-policy"
+)
+
+// SecurityChecks is all security checks in the order of reports.
+var SecurityChecks = []string{
+	CheckSecretScanning,
+	CheckDependabotUpdates,
+	CheckVulnerabilityAlerts,
+	CheckBranchProtection,
+	CheckWorkflowPermissions,
+	CheckForkPRApproval,
+	CheckSecurityPolicy,
+}
+
+var securityRemediations = map[string]string{
+	CheckSecretScanning:      "Enable secret scanning in Settings > Code security and analysis.",
+	CheckDependabotUpdates:   "Enable Dependabot security updates in Settings > Code security and analysis.",
+	CheckVulnerabilityAlerts: "Enable Dependabot alerts in Settings > Code security and analysis.",
+	CheckBranchProtection:    "Protect the default branch in Settings > Branches, require reviews before merging.",
+	CheckWorkflowPermissions: "Set workflow permissions to read repository contents in Settings > Actions > General.",
+	CheckForkPRApproval:      "Require approval for workflows from outside contributors in Settings > Actions > General.",
+	CheckSecurityPolicy:      "Add SECURITY.md to the repo, or to the .github repo of the org.",
+}
+
+// SecurityCheck is the result of a security check.
+type SecurityCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Skipped means the check doesn't apply to the repo or the setting is
+	// not visible to us, skipped checks don't count in the score.
+	Skipped bool   `json:"skipped,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+	// Fixable means the setting can be changed via API.
+	Fixable     bool   `json:"fixable,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// NewSecurityCheck creates a check result with remediation hint for failed
+// checks.
+func NewSecurityCheck(name string, passed bool, detail string) SecurityCheck {
+	c := SecurityCheck{
+		Name:   name,
+		Passed: passed,
+		Detail: detail,
+	}
+	if !passed {
+		c.Remediation = securityRemediations[name]
+	}
+	return c
+}
+
+// SkippedSecurityCheck creates a check result which doesn't count.
+func SkippedSecurityCheck(name, detail string) SecurityCheck {
+	return SecurityCheck{
+		Name:    name,
+		Skipped: true,
+		Detail:  detail,
+	}
+}
+
+// SecurityReport is the security posture of a repo.
+type SecurityReport struct {
+	Repo   string          `json:"repo"`
+	Score  int             `json:"score"`
+	Checks []SecurityCheck `json:"checks"`
+}
+
+// NewSecurityReport creates a report with checks ordered as SecurityChecks
+// and the score calculated.
+func NewSecurityReport(repo string, checks []SecurityCheck) SecurityReport {
+	order := make(map[string]int)
+	for i, v := range SecurityChecks {
+		order[v] = i
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		return order[checks[i].Name] < order[checks[j].Name]
+	})
+
+	r := SecurityReport{
+		Repo:   repo,
+		Score:  100,
+		Checks: checks,
+	}
+	total, passed := 0, 0
+	for _, c := range checks {
+		if c.Skipped {
+			continue
+		}
+		total++
+		if c.Passed {
+			passed++
+		}
+	}
+	if total > 0 {
+		r.Score = passed * 100 / total
+	}
+	return r
+}
+
+// Check returns the check result by name.
+func (r SecurityReport) Check(name string) (SecurityCheck, bool) {
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return SecurityCheck{}, false
+}
+
+// FormatSecurityReports renders reports as a markdown table followed by
+// remediation hints of failed checks.
+func FormatSecurityReports(rs []SecurityReport) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "| Repo | Score | %s |\n", strings.Join(SecurityChecks, " | "))
+	fmt.Fprintf(b, "| ---- | ---- |%s\n", strings.Repeat(" ---- |", len(SecurityChecks)))
+	for _, r := range rs {
+		fmt.Fprintf(b, "| %s | %d |", r.Repo, r.Score)
+		for _, name := range SecurityChecks {
+			c, ok := r.Check(name)
+			switch {
+			case !ok || c.Skipped:
+				fmt.Fprintf(b, " - |")
+			case c.Passed:
+				fmt.Fprintf(b, " ok |")
+			default:
+				fmt.Fprintf(b, " **fail** |")
+			}
+		}
+		fmt.Fprintf(b, "\n")
+	}
+
+	for _, r := range rs {
+		failed := make([]SecurityCheck, 0)
+		for _, c := range r.Checks {
+			if !c.Passed && !c.Skipped {
+				failed = append(failed, c)
+			}
+		}
+		if len(failed) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(b, "\n## %s\n\n", r.Repo)
+		for _, c := range failed {
+			fmt.Fprintf(b, "- %s: %s", c.Name, c.Remediation)
+			if c.Detail != "" {
+				fmt.Fprintf(b, " (%s)", c.Detail)
+			}
+			if c.Fixable {
+				fmt.Fprintf(b, " Fixable with `--fix`.")
+			}
+			fmt.Fprintf(b, "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSecurityReport(t *testing.T) {
+	workflow := NewSecurityCheck(CheckWorkflowPermissions, false, "default permission is write")
+	workflow.Fixable = true
+
+	r := NewSecurityReport("go-storage", []SecurityCheck{
+		NewSecurityCheck(CheckSecurityPolicy, true, ""),
+		workflow,
+		NewSecurityCheck(CheckBranchProtection, true, ""),
+		SkippedSecurityCheck(CheckSecretScanning, "not visible"),
+		NewSecurityCheck(CheckVulnerabilityAlerts, true, ""),
+	})
+	assert.Equal(t, 75, r.Score)
+	assert.Equal(t, CheckSecretScanning, r.Checks[0].Name)
+	assert.Equal(t, CheckSecurityPolicy, r.Checks[4].Name)
+
+	c, ok := r.Check(CheckWorkflowPermissions)
+	assert.True(t, ok)
+	assert.NotEmpty(t, c.Remediation)
+
+	assert.Equal(t, 100, NewSecurityReport("empty", nil).Score)
+
+	assert.Equal(t, `| Repo | Score | secret-scanning | dependabot-updates | vulnerability-alerts | branch-protection | workflow-permissions | fork-pr-approval | security-policy |
+| ---- | ---- | ---- | ---- | ---- | ---- | ---- | ---- | ---- |
+| go-storage | 75 | - | - | ok | ok | **fail** | - | ok |
+
+## go-storage
+
+- workflow-permissions: Set workflow permissions to read repository contents in Settings > Actions > General. (default permission is write) Fixable with `+"`--fix`"+`.
+`, FormatSecurityReports([]SecurityReport{r}))
+}
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Snapshot is the statistic of a repo computed by a report run, snapshots
+// are stored as JSON lines so that every run only appends to the file.
+type Snapshot struct {
+	Time  time.Time `json:"time"`
+	Owner string    `json:"owner,omitempty"`
+	Repo  string    `json:"repo"`
+	Statistic
+}
+
+// AppendSnapshots appends snapshots to path, the file will be created if
+// not exist.
+func AppendSnapshots(path string, ss []Snapshot) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	b := &strings.Builder{}
+	for _, s := range ss {
+		bs, err := json.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("json marshal: %v", err)
+		}
+		b.Write(bs)
+		b.WriteByte('\n')
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("write file %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+// LoadSnapshots loads all snapshots from path, a missing file means no
+// report has been stored yet.
+func LoadSnapshots(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	ss := make([]Snapshot, 0)
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("json unmarshal %s line %d: %v", path, line, err)
+		}
+		ss = append(ss, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read file %s: %v", path, err)
+	}
+	return ss, nil
+}
+
+// WeeklyStatistic is the statistic of all repos in an ISO week.
+type WeeklyStatistic struct {
+	// Week is like "2021-W32".
+	Week string
+	Statistic
+}
+
+// SnapshotWeeks sums snapshots of the owner by ISO week, ordered from the
+// oldest week. Snapshots without owner are stored by old versions and
+// counted for any owner.
+//
+// Only the latest snapshot of a repo in a week is counted, so that running
+// the report twice in a week doesn't count twice. Weeks without any run
+// between the oldest and the latest week are filled with zero statistic.
+func SnapshotWeeks(ss []Snapshot, owner string) []WeeklyStatistic {
+	latest := make(map[string]map[string]Snapshot)
+	var first, last time.Time
+	for _, s := range ss {
+		if s.Owner != "" && s.Owner != owner {
+			continue
+		}
+		if first.IsZero() || s.Time.Before(first) {
+			first = s.Time
+		}
+		if last.IsZero() || s.Time.After(last) {
+			last = s.Time
+		}
+
+		key := isoWeek(s.Time)
+		if latest[key] == nil {
+			latest[key] = make(map[string]Snapshot)
+		}
+		if old, ok := latest[key][s.Repo]; ok && old.Time.After(s.Time) {
+			continue
+		}
+		latest[key][s.Repo] = s
+	}
+
+	weeks := make([]WeeklyStatistic, 0, len(latest))
+	if len(latest) == 0 {
+		return weeks
+	}
+	// Step by days of the week of first, so that every ISO week between
+	// first and last is visited once.
+	end := isoWeek(last)
+	for t := first.UTC(); ; t = t.AddDate(0, 0, 7) {
+		key := isoWeek(t)
+		stats := make(Statistics, 0, len(latest[key]))
+		for _, s := range latest[key] {
+			stats = append(stats, s.Statistic)
+		}
+		weeks = append(weeks, WeeklyStatistic{Week: key, Statistic: stats.Sum()})
+		if key == end {
+			break
+		}
+	}
+	return weeks
+}
+
+// isoWeek formats the ISO week of t like "2021-W32".
+func isoWeek(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// sparks are levels of sparklines from the lowest.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a sparkline scaled between the min and max.
+func Sparkline(values []uint) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	rs := make([]rune, 0, len(values))
+	for _, v := range values {
+		level := 0
+		if max > min {
+			level = int((v - min) * uint(len(sparks)-1) / (max - min))
+		}
+		rs = append(rs, sparks[level])
+	}
+	return string(rs)
+}
+
+// FormatTrend formats the latest week with the delta against the week
+// before and sparklines of all weeks, like "PRs opened: 14 (+3) ▂▅█".
+func FormatTrend(weeks []WeeklyStatistic) string {
+	b := &strings.Builder{}
+	b.WriteString("\n## Trend\n\n")
+	if len(weeks) == 0 {
+		b.WriteString("No snapshots yet.\n")
+		return b.String()
+	}
+	fmt.Fprintf(b, "Week %s, sparklines cover the last %d weeks.\n\n", weeks[len(weeks)-1].Week, len(weeks))
+
+	metrics := []struct {
+		name  string
+		value func(Statistic) uint
+	}{
+		{"PRs opened", func(s Statistic) uint { return s.PROpened }},
+		{"PRs closed", func(s Statistic) uint { return s.PRClosed }},
+		{"Issues opened", func(s Statistic) uint { return s.IssueOpened }},
+		{"Issues closed", func(s Statistic) uint { return s.IssueClosed }},
+	}
+	for _, m := range metrics {
+		values := make([]uint, 0, len(weeks))
+		for _, w := range weeks {
+			values = append(values, m.value(w.Statistic))
+		}
+
+		current := values[len(values)-1]
+		fmt.Fprintf(b, "- %s: %d", m.name, current)
+		if len(values) > 1 {
+			fmt.Fprintf(b, " (%+d)", int(current)-int(values[len(values)-2]))
+		}
+		fmt.Fprintf(b, " %s\n", Sparkline(values))
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSnapshots(t *testing.T) {
+	ss, err := LoadSnapshots("testdata/snapshots.jsonl")
+	assert.NoError(t, err)
+	assert.Len(t, ss, 6)
+	assert.Equal(t, "go-service-s3", ss[1].Repo)
+	assert.Equal(t, uint(2), ss[1].PROpened)
+
+	ss, err = LoadSnapshots("testdata/not-exist.jsonl")
+	assert.NoError(t, err)
+	assert.Empty(t, ss)
+}
+
+func TestSnapshotWeeks(t *testing.T) {
+	ss, err := LoadSnapshots("testdata/snapshots.jsonl")
+	assert.NoError(t, err)
+
+	// Snapshots of other are ignored, and W34 without any run is filled.
+	weeks := SnapshotWeeks(ss, "beyondstorage")
+	assert.Equal(t, []WeeklyStatistic{
+		{
+			Week: "2021-W32",
+			// The second run of go-storage replaces the first one.
+			Statistic: Statistic{PROpened: 8, PRClosed: 6, IssueOpened: 4, IssueClosed: 1},
+		},
+		{
+			Week:      "2021-W33",
+			Statistic: Statistic{PROpened: 9, PRClosed: 7, IssueOpened: 1, IssueClosed: 2},
+		},
+		{Week: "2021-W34"},
+		{
+			Week:      "2021-W35",
+			Statistic: Statistic{PROpened: 4, PRClosed: 3, IssueOpened: 2},
+		},
+	}, weeks)
+
+	// Snapshots without owner are counted for other as well.
+	weeks = SnapshotWeeks(ss, "other")
+	assert.Len(t, weeks, 2)
+	assert.Equal(t, uint(100), weeks[1].PROpened)
+	assert.Empty(t, SnapshotWeeks(nil, "beyondstorage"))
+}
+
+func TestAppendSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.jsonl")
+	at := time.Date(2021, 8, 16, 8, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		err := AppendSnapshots(path, []Snapshot{
+			{Time: at, Owner: "beyondstorage", Repo: "go-storage", Statistic: Statistic{PROpened: uint(i)}},
+		})
+		assert.NoError(t, err)
+	}
+
+	ss, err := LoadSnapshots(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Snapshot{
+		{Time: at, Owner: "beyondstorage", Repo: "go-storage", Statistic: Statistic{PROpened: 0}},
+		{Time: at, Owner: "beyondstorage", Repo: "go-storage", Statistic: Statistic{PROpened: 1}},
+	}, ss)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "", Sparkline(nil))
+	assert.Equal(t, "▁▁", Sparkline([]uint{3, 3}))
+	assert.Equal(t, "▁▄█▁", Sparkline([]uint{2, 5, 9, 2}))
+}
+
+func TestFormatTrend(t *testing.T) {
+	assert.Equal(t, "\n## Trend\n\nNo snapshots yet.\n", FormatTrend(nil))
+
+	ss, err := LoadSnapshots("testdata/snapshots.jsonl")
+	assert.NoError(t, err)
+	assert.Equal(t, `
+## Trend
+
+Week 2021-W35, sparklines cover the last 4 weeks.
+
+- PRs opened: 4 (+4) ▇█▁▄
+- PRs closed: 3 (+3) ▇█▁▄
+- Issues opened: 2 (+2) █▂▁▄
+- Issues closed: 0 (+0) ▄█▁▁
+`, FormatTrend(SnapshotWeeks(ss, "beyondstorage")))
+}
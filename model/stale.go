@@ -0,0 +1,228 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultStaleInactive = Duration(60 * 24 * time.Hour)
+	defaultStaleGrace    = Duration(7 * 24 * time.Hour)
+	defaultStaleLabel    = "stale"
+
+	// staleActivitySlack tolerates updates caused by marking the item
+	// stale, activities after it unmark the item.
+	staleActivitySlack = time.Minute
+)
+
+// Actions taken on stale issues and pull requests.
+const (
+	StaleMark   = "mark"
+	StaleUnmark = "unmark"
+	StaleClose  = "close"
+)
+
+// StalePolicy decides when open issues and pull requests are stale.
+//
+// The policy in community.toml applies to all repos, repos.toml could
+// override it per repo with a stale table. Non-zero fields override the
+// config, switches set to false explicitly turn off the inherited ones, and
+// exempt labels prefixed with "-" remove the inherited label.
+type StalePolicy struct {
+	// Inactive is how long an item without activity will be marked stale,
+	// 60d by default.
+	Inactive Duration `toml:"inactive,omitempty"`
+	// Grace is how long a stale item is kept open before closing, 7d by
+	// default.
+	Grace Duration `toml:"grace,omitempty"`
+	// Label marks stale items, "stale" by default.
+	Label string `toml:"label,omitempty"`
+	// ExemptLabels are labels of items which never get stale.
+	ExemptLabels []string `toml:"exempt_labels,omitempty"`
+	// Comment and CloseComment are posted when marking and closing items.
+	Comment      string `toml:"comment,omitempty"`
+	CloseComment string `toml:"close_comment,omitempty"`
+
+	// Switches are pointers so that repos could turn them off, use
+	// IsExcluded and IsDisabled to read them.
+	ExcludeIssues *bool `toml:"exclude_issues,omitempty"`
+	ExcludePRs    *bool `toml:"exclude_prs,omitempty"`
+	// Disabled skips the triage of repos entirely.
+	Disabled *bool `toml:"disabled,omitempty"`
+}
+
+// IsExcluded checks whether issues or pull requests are excluded.
+func (p StalePolicy) IsExcluded(pr bool) bool {
+	if pr {
+		return p.ExcludePRs != nil && *p.ExcludePRs
+	}
+	return p.ExcludeIssues != nil && *p.ExcludeIssues
+}
+
+func (p StalePolicy) IsDisabled() bool {
+	return p.Disabled != nil && *p.Disabled
+}
+
+// StaleItem is an open issue or pull request to triage.
+type StaleItem struct {
+	Number      int
+	PullRequest bool
+	Labels      []string
+	UpdatedAt   time.Time
+	// MarkedAt is when the stale label was added, zero if unknown.
+	MarkedAt time.Time
+}
+
+func (p StalePolicy) GetInactive() time.Duration {
+	if p.Inactive == 0 {
+		return time.Duration(defaultStaleInactive)
+	}
+	return time.Duration(p.Inactive)
+}
+
+func (p StalePolicy) GetGrace() time.Duration {
+	if p.Grace == 0 {
+		return time.Duration(defaultStaleGrace)
+	}
+	return time.Duration(p.Grace)
+}
+
+func (p StalePolicy) GetLabel() string {
+	if p.Label == "" {
+		return defaultStaleLabel
+	}
+	return p.Label
+}
+
+// GetComment returns the comment posted when marking items stale.
+func (p StalePolicy) GetComment(pr bool) string {
+	if p.Comment != "" {
+		return p.Comment
+	}
+	return fmt.Sprintf("This %s has been marked as stale because it has no activity in %d days. It will be closed in %d days if no further activity occurs.",
+		staleKind(pr), days(p.GetInactive()), days(p.GetGrace()))
+}
+
+// GetCloseComment returns the comment posted when closing stale items.
+func (p StalePolicy) GetCloseComment(pr bool) string {
+	if p.CloseComment != "" {
+		return p.CloseComment
+	}
+	return fmt.Sprintf("This %s has been closed because it has been stale for %d days. Feel free to reopen it if it's still relevant.",
+		staleKind(pr), days(p.GetGrace()))
+}
+
+// Merge returns the policy overridden by the policy of a repo.
+func (p StalePolicy) Merge(o *StalePolicy) StalePolicy {
+	if o == nil {
+		return p
+	}
+	x := p.override(*o)
+	x.ExemptLabels = mergeList(nil, x.ExemptLabels)
+	return x
+}
+
+// override sets non-zero fields and set switches of o into p. Exempt labels
+// are appended as is, so that removals could be applied to labels of the
+// config later.
+func (p StalePolicy) override(o StalePolicy) StalePolicy {
+	if o.Inactive != 0 {
+		p.Inactive = o.Inactive
+	}
+	if o.Grace != 0 {
+		p.Grace = o.Grace
+	}
+	if o.Label != "" {
+		p.Label = o.Label
+	}
+	if o.Comment != "" {
+		p.Comment = o.Comment
+	}
+	if o.CloseComment != "" {
+		p.CloseComment = o.CloseComment
+	}
+	p.ExemptLabels = append(append([]string(nil), p.ExemptLabels...), o.ExemptLabels...)
+	if o.ExcludeIssues != nil {
+		p.ExcludeIssues = o.ExcludeIssues
+	}
+	if o.ExcludePRs != nil {
+		p.ExcludePRs = o.ExcludePRs
+	}
+	if o.Disabled != nil {
+		p.Disabled = o.Disabled
+	}
+	return p
+}
+
+// inheritStalePolicy merges the policy of a repo pattern on top of base.
+func inheritStalePolicy(base, o *StalePolicy) *StalePolicy {
+	if o == nil {
+		return base
+	}
+	x := StalePolicy{}
+	if base != nil {
+		x = *base
+	}
+	x = x.override(*o)
+	return &x
+}
+
+// Decide returns the action to take on the item at now, empty if nothing
+// should be done.
+//
+// Items without activity in Inactive are marked, marked items are closed
+// after Grace, and unmarked if there are activities after marking or they
+// get exempt labels.
+func (p StalePolicy) Decide(item StaleItem, now time.Time) string {
+	if p.IsDisabled() || p.IsExcluded(item.PullRequest) {
+		return ""
+	}
+
+	marked, exempt := false, false
+	for _, label := range item.Labels {
+		for _, v := range p.ExemptLabels {
+			if label == v {
+				exempt = true
+			}
+		}
+		if label == p.GetLabel() {
+			marked = true
+		}
+	}
+	if exempt {
+		if marked {
+			return StaleUnmark
+		}
+		return ""
+	}
+
+	if !marked {
+		if now.Sub(item.UpdatedAt) >= p.GetInactive() {
+			return StaleMark
+		}
+		return ""
+	}
+
+	markedAt := item.MarkedAt
+	if markedAt.IsZero() {
+		markedAt = item.UpdatedAt
+	}
+	if item.UpdatedAt.After(markedAt.Add(staleActivitySlack)) {
+		return StaleUnmark
+	}
+	if now.Sub(markedAt) >= p.GetGrace() {
+		return StaleClose
+	}
+	return ""
+}
+
+func staleKind(pr bool) string {
+	if pr {
+		return "pull request"
+	}
+	return "issue"
+}
+
+func days(d time.Duration) int {
+	return int(d.Hours() / 24)
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStalePolicy_Merge(t *testing.T) {
+	x, err := LoadRepos("testdata/repos-inherit.toml", []string{"go-service-s3"})
+	if err != nil {
+		t.Fatal("load repos", err)
+	}
+
+	p := StalePolicy{
+		Inactive:     Duration(90 * 24 * time.Hour),
+		ExemptLabels: []string{"pinned", "security"},
+		Disabled:     boolPtr(true),
+	}.Merge(x["go-service-s3"].Stale)
+	// Switches turned on by config and go-service-* are turned off by
+	// go-service-s3.
+	assert.Equal(t, StalePolicy{
+		Inactive:     Duration(30 * 24 * time.Hour),
+		Grace:        Duration(14 * 24 * time.Hour),
+		ExemptLabels: []string{"security", "needs-design"},
+		ExcludePRs:   boolPtr(false),
+		Disabled:     boolPtr(false),
+	}, p)
+	assert.False(t, p.IsDisabled())
+	assert.False(t, p.IsExcluded(true))
+
+	assert.Equal(t, StalePolicy{Label: "wontfix"}, StalePolicy{Label: "wontfix"}.Merge(nil))
+}
+
+func TestStalePolicy_Decide(t *testing.T) {
+	now := time.Date(2021, 8, 16, 8, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	p := StalePolicy{ExemptLabels: []string{"pinned"}}
+
+	cases := []struct {
+		name   string
+		item   StaleItem
+		expect string
+	}{
+		{"active", StaleItem{UpdatedAt: now.Add(-10 * day)}, ""},
+		{"inactive", StaleItem{UpdatedAt: now.Add(-60 * day)}, StaleMark},
+		{"exempt", StaleItem{UpdatedAt: now.Add(-60 * day), Labels: []string{"pinned"}}, ""},
+		{"in grace", StaleItem{
+			Labels:    []string{"stale"},
+			UpdatedAt: now.Add(-3 * day),
+			MarkedAt:  now.Add(-3 * day),
+		}, ""},
+		{"after grace", StaleItem{
+			Labels:    []string{"stale"},
+			UpdatedAt: now.Add(-7 * day).Add(30 * time.Second),
+			MarkedAt:  now.Add(-7 * day),
+		}, StaleClose},
+		{"active after marked", StaleItem{
+			Labels:    []string{"stale"},
+			UpdatedAt: now.Add(-1 * day),
+			MarkedAt:  now.Add(-8 * day),
+		}, StaleUnmark},
+		{"exempt after marked", StaleItem{
+			Labels:    []string{"stale", "pinned"},
+			UpdatedAt: now.Add(-3 * day),
+			MarkedAt:  now.Add(-3 * day),
+		}, StaleUnmark},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expect, p.Decide(c.item, now), c.name)
+	}
+
+	p.ExcludePRs = boolPtr(true)
+	assert.Equal(t, "", p.Decide(StaleItem{PullRequest: true, UpdatedAt: now.Add(-60 * day)}, now))
+	p.Disabled = boolPtr(true)
+	assert.Equal(t, "", p.Decide(StaleItem{UpdatedAt: now.Add(-60 * day)}, now))
+}
+
+func TestStalePolicy_Comment(t *testing.T) {
+	p := StalePolicy{Inactive: Duration(30 * 24 * time.Hour)}
+	assert.Equal(t, "This pull request has been marked as stale because it has no activity in 30 days. It will be closed in 7 days if no further activity occurs.", p.GetComment(true))
+	assert.Equal(t, "This issue has been closed because it has been stale for 7 days. Feel free to reopen it if it's still relevant.", p.GetCloseComment(false))
+
+	p.Comment = "Is this still relevant?"
+	assert.Equal(t, "Is this still relevant?", p.GetComment(false))
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -4,10 +4,10 @@ import "fmt"
 
 // Statistic count events we needed
 type Statistic struct {
-	PROpened    uint
-	PRClosed    uint
-	IssueOpened uint
-	IssueClosed uint
+	PROpened    uint `json:"pr_opened"`
+	PRClosed    uint `json:"pr_closed"`
+	IssueOpened uint `json:"issue_opened"`
+	IssueClosed uint `json:"issue_closed"`
 }
 
 type Statistics []Statistic
@@ -3,16 +3,57 @@ package model
 import (
 	"fmt"
 	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/gobwas/glob"
 )
 
 type Teams map[string]Team
 
 type Team struct {
-	Project string
-	Role    Role
-	Members []string
+	Project string   `toml:"project,omitempty"`
+	Role    Role     `toml:"role"`
+	Members []string `toml:"members"`
+	// Emeritus are former members who are still credited, but don't have
+	// access granted by the team anymore.
+	Emeritus []string `toml:"emeritus,omitempty"`
+
+	// Parent is the name of the parent team, the team inherits access of
+	// its parent on github.
+	Parent      string `toml:"parent,omitempty"`
+	Description string `toml:"description,omitempty"`
+	// Privacy is "closed" (visible to all org members) or "secret", teams
+	// are closed by default. Secret teams can't be nested.
+	Privacy string `toml:"privacy,omitempty"`
+	// Maintainers are members who can manage the team, they must be listed
+	// in members as well.
+	Maintainers []string `toml:"maintainers,omitempty"`
+}
+
+const (
+	TeamPrivacyClosed = "closed"
+	TeamPrivacySecret = "secret"
+)
+
+// GetPrivacy returns the privacy of the team, closed by default.
+func (t Team) GetPrivacy() string {
+	if t.Privacy == "" {
+		return TeamPrivacyClosed
+	}
+	return t.Privacy
+}
+
+// IsMaintainer checks whether login maintains the team.
+func (t Team) IsMaintainer(login string) bool {
+	for _, v := range t.Maintainers {
+		if v == login {
+			return true
+		}
+	}
+	return false
 }
 
 func LoadTeams(path string) (Teams, error) {
@@ -29,3 +70,317 @@ func LoadTeams(path string) (Teams, error) {
 
 	return x, nil
 }
+
+// SortedNames returns names of all teams with parents before their
+// children, teams on the same level are sorted by name.
+func (t Teams) SortedNames() ([]string, error) {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	sorted := make([]string, 0, len(t))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("team parent cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+
+		team := t[name]
+		if team.Parent != "" {
+			parent, ok := t[team.Parent]
+			if !ok {
+				return fmt.Errorf("team %s has unknown parent %s", name, team.Parent)
+			}
+			if team.GetPrivacy() == TeamPrivacySecret || parent.GetPrivacy() == TeamPrivacySecret {
+				return fmt.Errorf("team %s: secret teams can't be nested", name)
+			}
+			if err := visit(team.Parent, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		sorted = append(sorted, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// Descendants returns names of all teams nested under the team.
+func (t Teams) Descendants(name string) []string {
+	ds := make([]string, 0)
+	for n, team := range t {
+		for p, depth := team.Parent, 0; p != "" && depth < len(t); p, depth = t[p].Parent, depth+1 {
+			if p == name {
+				ds = append(ds, n)
+				break
+			}
+		}
+	}
+	sort.Strings(ds)
+	return ds
+}
+
+// RoleOf returns the highest role of login in the project, teams without
+// project apply to all projects.
+//
+// Users not in any team are contributors.
+func (t Teams) RoleOf(project, login string) Role {
+	role := RoleContributor
+	for _, team := range t {
+		if team.Project != "" && team.Project != project {
+			continue
+		}
+		for _, m := range team.Members {
+			if m == login && team.Role.rank() > role.rank() {
+				role = team.Role
+			}
+		}
+	}
+	return role
+}
+
+// TeamOf returns the team for the role in the project. A team named like
+// "<project>-<role>" will be returned if no such team exists.
+func (t Teams) TeamOf(project string, role Role) string {
+	names := make([]string, 0)
+	for name, team := range t {
+		if team.Project == project && team.Role == role {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("%s-%s", project, role)
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// MoveToEmeritus moves members into the emeritus list of their teams in
+// teams.toml content. moves is keyed by team name.
+//
+// Only the members and emeritus keys of touched teams are rewritten, so
+// comments and formatting of other parts are kept. Trailing comments of
+// entries in multiline arrays move along with their entries.
+func MoveToEmeritus(data []byte, moves map[string][]string) ([]byte, error) {
+	var teams Teams
+	md, err := toml.Decode(string(data), &teams)
+	if err != nil {
+		return nil, fmt.Errorf("toml unmarshal: %v", err)
+	}
+
+	type edit struct {
+		start, end int
+		content    []string
+	}
+	edits := make([]edit, 0)
+
+	lines := strings.Split(string(data), "\n")
+	pos := keyPositions(data, md.Keys())
+	for name, logins := range moves {
+		t, ok := teams[name]
+		if !ok {
+			return nil, fmt.Errorf("team %s is not found", name)
+		}
+
+		moved := make(map[string]struct{})
+		for _, v := range logins {
+			moved[v] = struct{}{}
+		}
+		members := make([]string, 0, len(t.Members))
+		for _, v := range t.Members {
+			if _, ok := moved[v]; !ok {
+				members = append(members, v)
+			}
+		}
+		emeritus := mergeList(t.Emeritus, logins)
+
+		line, ok := pos[toml.Key{name, "members"}.String()]
+		if !ok {
+			return nil, fmt.Errorf("team %s doesn't have members", name)
+		}
+		start, end := line-1, arrayEnd(lines, line-1)
+		indent := lines[start][:len(lines[start])-len(strings.TrimLeft(lines[start], " \t"))]
+		multiline := end > start
+
+		comments := arrayComments(lines[start : end+1])
+		if line, ok := pos[toml.Key{name, "emeritus"}.String()]; ok {
+			es := line - 1
+			for k, v := range arrayComments(lines[es : arrayEnd(lines, es)+1]) {
+				comments[k] = v
+			}
+		}
+
+		content := renderArray(indent, "members", members, multiline, comments)
+		content = append(content, renderArray(indent, "emeritus", emeritus, multiline, comments)...)
+
+		if line, ok := pos[toml.Key{name, "emeritus"}.String()]; ok {
+			es, ee := line-1, arrayEnd(lines, line-1)
+			if es < start {
+				// Keep emeritus in place, members will be rendered there.
+				edits = append(edits, edit{start, end, nil}, edit{es, ee, content})
+				continue
+			}
+			edits = append(edits, edit{es, ee, nil})
+		}
+		edits = append(edits, edit{start, end, content})
+	}
+
+	// Apply edits from the bottom so that line numbers are not shifted.
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	for _, e := range edits {
+		tail := append([]string{}, lines[e.end+1:]...)
+		lines = append(append(lines[:e.start], e.content...), tail...)
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// arrayEnd returns the line where the array starting at line ends.
+func arrayEnd(lines []string, start int) int {
+	depth := 0
+	var quote rune
+line:
+	for i := start; i < len(lines); i++ {
+		for _, c := range lines[i] {
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#':
+				continue line
+			case c == '[':
+				depth++
+			case c == ']':
+				depth--
+				if depth == 0 {
+					return i
+				}
+			}
+		}
+	}
+	return start
+}
+
+// arrayComments returns trailing comments of entries in array lines, keyed
+// by the entry. Lines with several entries share the comment.
+func arrayComments(lines []string) map[string]string {
+	comments := make(map[string]string)
+	for _, line := range lines {
+		values := make([]string, 0)
+		var quote rune
+		begin := 0
+	scan:
+		for i, c := range line {
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+					values = append(values, line[begin:i])
+				}
+			case c == '"' || c == '\'':
+				quote, begin = c, i+1
+			case c == '#':
+				for _, v := range values {
+					comments[v] = strings.TrimSpace(line[i:])
+				}
+				break scan
+			}
+		}
+	}
+	return comments
+}
+
+func renderArray(indent, key string, values []string, multiline bool, comments map[string]string) []string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, strconv.Quote(v))
+	}
+	if !multiline || len(values) == 0 {
+		return []string{fmt.Sprintf("%s%s = [%s]", indent, key, strings.Join(quoted, ", "))}
+	}
+
+	lines := []string{fmt.Sprintf("%s%s = [", indent, key)}
+	for i, v := range quoted {
+		line := fmt.Sprintf("%s    %s,", indent, v)
+		if c, ok := comments[values[i]]; ok {
+			line += " " + c
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, indent+"]")
+}
+
+const (
+	UnmanagedReport = "report"
+	UnmanagedRevoke = "revoke"
+	UnmanagedDelete = "delete"
+)
+
+// UnmanagedTeamPolicy decides how to handle teams which exist on github but
+// not in teams.toml.
+type UnmanagedTeamPolicy struct {
+	// Mode is one of "report", "revoke" (remove all repo access of the team)
+	// and "delete", report by default.
+	Mode string `toml:"mode"`
+	// Allow is glob patterns of team names which are kept as is.
+	Allow []string `toml:"allow"`
+}
+
+// GetMode returns the mode of the policy, report by default.
+func (p UnmanagedTeamPolicy) GetMode() string {
+	if p.Mode == "" {
+		return UnmanagedReport
+	}
+	return p.Mode
+}
+
+func (p UnmanagedTeamPolicy) Validate() error {
+	switch p.GetMode() {
+	case UnmanagedReport, UnmanagedRevoke, UnmanagedDelete:
+	default:
+		return fmt.Errorf("invalid mode %q", p.Mode)
+	}
+	for _, v := range p.Allow {
+		if _, err := glob.Compile(v); err != nil {
+			return fmt.Errorf("compile pattern %s: %v", v, err)
+		}
+	}
+	return nil
+}
+
+// IsAllowed checks whether the team is kept by the allow list.
+func (p UnmanagedTeamPolicy) IsAllowed(name string) bool {
+	for _, v := range p.Allow {
+		g, err := glob.Compile(v)
+		if err != nil {
+			continue
+		}
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,3 +21,67 @@ func TestLoadTeams(t *testing.T) {
 	assert.Equal(t, RoleMaintainer, team.Role)
 	assert.ElementsMatch(t, []string{"test-user"}, team.Members)
 }
+
+func TestMoveToEmeritus(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/teams-emeritus.toml")
+	if err != nil {
+		t.Fatal("read file", err)
+	}
+
+	content, err := MoveToEmeritus(data, map[string][]string{
+		"pmc":                   {"bob"},
+		"go-storage-maintainer": {"bob"},
+	})
+	if err != nil {
+		t.Fatal("move to emeritus", err)
+	}
+
+	assert.Equal(t, `# Project management committee
+[pmc]
+role = "admin"
+members = ["alice"]
+emeritus = ["bob"]
+
+[go-storage-maintainer]
+project = "go-storage"
+role = "maintainer"
+members = [
+    "carol", # since 2022
+]
+emeritus = [
+    "dave",
+    "bob", # since 2021
+]
+
+[go-storage-committer]
+project = "go-storage"
+role = "committer"
+members = ["erin"]
+`, string(content))
+
+	_, err = MoveToEmeritus(data, map[string][]string{"not-exist": {"bob"}})
+	assert.Error(t, err)
+}
+
+func TestTeams_SortedNames(t *testing.T) {
+	teams := Teams{
+		"go-storage-committer":  {Parent: "go-storage-maintainer"},
+		"go-storage-maintainer": {Parent: "go-storage"},
+		"go-storage":            {},
+		"pmc":                   {Privacy: TeamPrivacySecret},
+	}
+	names, err := teams.SortedNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go-storage", "go-storage-maintainer", "go-storage-committer", "pmc"}, names)
+	assert.Equal(t, []string{"go-storage-committer", "go-storage-maintainer"}, teams.Descendants("go-storage"))
+	assert.Empty(t, teams.Descendants("pmc"))
+
+	_, err = Teams{"a": {Parent: "b"}, "b": {Parent: "a"}}.SortedNames()
+	assert.EqualError(t, err, "team parent cycle: a -> b -> a")
+
+	_, err = Teams{"a": {Parent: "unknown"}}.SortedNames()
+	assert.EqualError(t, err, "team a has unknown parent unknown")
+
+	_, err = Teams{"a": {Parent: "b"}, "b": {Privacy: TeamPrivacySecret}}.SortedNames()
+	assert.EqualError(t, err, "team a: secret teams can't be nested")
+}
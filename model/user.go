@@ -3,16 +3,33 @@ package model
 import (
 	"fmt"
 	"io/ioutil"
+	"regexp"
+	"sort"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
+// Users is the users registry keyed by github login.
 type Users map[string]User
 
 type User struct {
+	// Login is the github login, filled from the key in users.toml.
+	Login string `toml:"-"`
+	Name  string `toml:"name"`
 	Email string `toml:"email"`
+	// Matrix is the full matrix user id like "@alice:matrix.org".
+	Matrix string `toml:"matrix"`
+	// Affiliation is the company or organization the user works for.
+	Affiliation string `toml:"affiliation"`
+	// Timezone is an IANA time zone like "Asia/Shanghai".
+	Timezone string `toml:"timezone"`
+	// Links are social links like twitter or blog.
+	Links map[string]string `toml:"links"`
 }
 
+var matrixIDPattern = regexp.MustCompile(`^@[a-z0-9._=\-/]+:[A-Za-z0-9.\-]+(:\d+)?$`)
+
 func LoadUsers(path string) (Users, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -25,5 +42,65 @@ func LoadUsers(path string) (Users, error) {
 		return nil, fmt.Errorf("toml unmarshal: %v", err)
 	}
 
+	for login, u := range x {
+		u.Login = login
+		x[login] = u
+	}
 	return x, nil
 }
+
+// Validate checks fields that could be validated without github.
+func (u User) Validate() error {
+	if u.Matrix != "" && !matrixIDPattern.MatchString(u.Matrix) {
+		return fmt.Errorf("user %s has invalid matrix id %q", u.Login, u.Matrix)
+	}
+	if u.Timezone != "" {
+		if _, err := time.LoadLocation(u.Timezone); err != nil {
+			return fmt.Errorf("user %s has invalid timezone %q", u.Login, u.Timezone)
+		}
+	}
+	return nil
+}
+
+// DisplayName returns the name of login, or login itself if not known.
+func (x Users) DisplayName(login string) string {
+	if u, ok := x[login]; ok && u.Name != "" {
+		return u.Name
+	}
+	return login
+}
+
+// Affiliation returns the affiliation of login, or UnknownAffiliation.
+func (x Users) Affiliation(login string) string {
+	if u, ok := x[login]; ok && u.Affiliation != "" {
+		return u.Affiliation
+	}
+	return UnknownAffiliation
+}
+
+// Mention renders login as markdown reference like "Alice ([@alice])".
+func (x Users) Mention(login string) string {
+	if u, ok := x[login]; ok && u.Name != "" {
+		return fmt.Sprintf("%s ([@%s])", u.Name, login)
+	}
+	return fmt.Sprintf("[@%s]", login)
+}
+
+// UnknownMembers returns all team members and emeritus not in users.
+func (x Users) UnknownMembers(teams Teams) []string {
+	unknown := make(map[string]struct{})
+	for _, t := range teams {
+		for _, v := range append(append([]string{}, t.Members...), t.Emeritus...) {
+			if _, ok := x[v]; !ok {
+				unknown[v] = struct{}{}
+			}
+		}
+	}
+
+	logins := make([]string, 0, len(unknown))
+	for v := range unknown {
+		logins = append(logins, v)
+	}
+	sort.Strings(logins)
+	return logins
+}
@@ -12,5 +12,31 @@ func TestLoadUsers(t *testing.T) {
 		t.Fatal("load user", err)
 	}
 
-	assert.Equal(t, "user@example.com", x["example"].Email)
+	u := x["example"]
+	assert.Equal(t, "example", u.Login)
+	assert.Equal(t, "Example User", u.Name)
+	assert.Equal(t, "user@example.com", u.Email)
+	assert.Equal(t, "@example:matrix.org", u.Matrix)
+	assert.Equal(t, "Example Inc.", u.Affiliation)
+	assert.Equal(t, "https://twitter.com/example", u.Links["twitter"])
+	assert.NoError(t, u.Validate())
+
+	assert.Equal(t, "Example User ([@example])", x.Mention("example"))
+	assert.Equal(t, "[@unknown]", x.Mention("unknown"))
+	assert.Equal(t, "unknown", x.DisplayName("unknown"))
+}
+
+func TestUser_Validate(t *testing.T) {
+	assert.Error(t, User{Login: "a", Matrix: "example:matrix.org"}.Validate())
+	assert.Error(t, User{Login: "a", Timezone: "Mars/Olympus"}.Validate())
+}
+
+func TestUsers_UnknownMembers(t *testing.T) {
+	users := Users{"alice": {}}
+	teams := Teams{
+		"a": {Members: []string{"alice", "bob"}},
+		"b": {Members: []string{"bob"}, Emeritus: []string{"carol"}},
+	}
+
+	assert.Equal(t, []string{"bob", "carol"}, users.UnknownMembers(teams))
 }
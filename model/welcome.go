@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"text/template"
+)
+
+// DefaultWelcomeTemplate is used when WelcomePolicy doesn't specify a template.
+const DefaultWelcomeTemplate = `Hi @{{ .Login }}, thank you for your contributions{{ if .PullRequest }} like {{ .PullRequest }}{{ end }}!
+
+We have invited you to join the [{{ .Org }}](https://github.com/{{ .Org }}) organization as a **{{ .Role }}**. Please check your email or visit https://github.com/orgs/{{ .Org }}/invitation to accept it.
+{{ if .NextRole }}
+Keep contributing, and you could become a **{{ .NextRole }}** of the community.
+{{ end }}{{ if .DocsURL }}
+Learn more about our community at {{ .DocsURL }}.
+{{ end }}`
+
+// WelcomePolicy decides how newly invited contributors will be welcomed.
+type WelcomePolicy struct {
+	// Comment posts the message on the contributor's most recent merged PR.
+	Comment bool `toml:"comment"`
+	// Matrix sends the message via matrix direct message if the contributor's
+	// matrix id is known in users.toml.
+	Matrix bool `toml:"matrix"`
+	// Template is the path to a text/template file, DefaultWelcomeTemplate
+	// will be used if it's empty.
+	Template string `toml:"template"`
+	DocsURL  string `toml:"docs_url"`
+}
+
+// Welcome carries the data used to render welcome messages.
+type Welcome struct {
+	Login       string
+	Org         string
+	Role        Role
+	NextRole    Role
+	DocsURL     string
+	PullRequest string
+}
+
+func NewWelcome(login, org, docsURL, pullRequest string) Welcome {
+	next, _ := RoleContributor.Next()
+	return Welcome{
+		Login:       login,
+		Org:         org,
+		Role:        RoleContributor,
+		NextRole:    next,
+		DocsURL:     docsURL,
+		PullRequest: pullRequest,
+	}
+}
+
+// Enabled returns true if any welcome channel is enabled.
+func (p WelcomePolicy) Enabled() bool {
+	return p.Comment || p.Matrix
+}
+
+// LoadTemplate parses the welcome template.
+func (p WelcomePolicy) LoadTemplate() (*template.Template, error) {
+	content := DefaultWelcomeTemplate
+	if p.Template != "" {
+		data, err := ioutil.ReadFile(p.Template)
+		if err != nil {
+			return nil, fmt.Errorf("read file %s: %v", p.Template, err)
+		}
+		content = string(data)
+	}
+
+	t, err := template.New("welcome").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse welcome template: %v", err)
+	}
+	return t, nil
+}
+
+// Render renders the welcome message with given template.
+func (w Welcome) Render(t *template.Template) (string, error) {
+	b := &strings.Builder{}
+	err := t.Execute(b, w)
+	if err != nil {
+		return "", fmt.Errorf("render welcome template: %v", err)
+	}
+	return b.String(), nil
+}
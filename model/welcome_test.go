@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWelcome_Render(t *testing.T) {
+	tmpl, err := WelcomePolicy{}.LoadTemplate()
+	if err != nil {
+		t.Fatal("load template", err)
+	}
+
+	w := NewWelcome("alice", "beyondstorage", "https://beyondstorage.io/community", "https://github.com/beyondstorage/go-storage/pull/1")
+	assert.Equal(t, RoleReviewer, w.NextRole)
+
+	content, err := w.Render(tmpl)
+	if err != nil {
+		t.Fatal("render", err)
+	}
+	assert.Contains(t, content, "Hi @alice, thank you for your contributions like https://github.com/beyondstorage/go-storage/pull/1!")
+	assert.Contains(t, content, "as a **contributor**")
+	assert.Contains(t, content, "become a **reviewer**")
+	assert.Contains(t, content, "https://beyondstorage.io/community")
+}
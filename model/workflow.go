@@ -0,0 +1,202 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules of workflow findings.
+const (
+	RulePullRequestTargetCheckout = "pull-request-target-checkout"
+	RuleUnpinnedAction            = "unpinned-action"
+	RuleWriteAllPermissions       = "write-all-permissions"
+	RuleSecretsInUntrustedRun     = "secrets-in-untrusted-run"
+)
+
+// untrustedTriggers are events which could be triggered by anyone and run
+// with secrets of the repo.
+var untrustedTriggers = map[string]struct{}{
+	"pull_request_target":         {},
+	"issue_comment":               {},
+	"issues":                      {},
+	"pull_request_review_comment": {},
+	"discussion":                  {},
+	"discussion_comment":          {},
+	"workflow_run":                {},
+}
+
+// firstPartyOwners are owners of actions maintained by github.
+var firstPartyOwners = map[string]struct{}{
+	"actions": {},
+	"github":  {},
+}
+
+var (
+	commitSHARegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	prHeadRegexp    = regexp.MustCompile(`github\.event\.pull_request\.head\.|github\.head_ref`)
+	secretsRegexp   = regexp.MustCompile(`\$\{\{[^}]*secrets\.`)
+)
+
+// WorkflowFinding is a risky pattern found in a workflow file.
+type WorkflowFinding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (f WorkflowFinding) String() string {
+	return fmt.Sprintf("%s:%d: [%s] %s", f.File, f.Line, f.Rule, f.Message)
+}
+
+// AnalyzeWorkflow finds risky patterns in a github workflow file.
+func AnalyzeWorkflow(file string, data []byte) (fs []WorkflowFinding, err error) {
+	var doc yaml.Node
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("yaml unmarshal %s: %v", file, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	root := doc.Content[0]
+
+	add := func(n *yaml.Node, rule, format string, args ...interface{}) {
+		fs = append(fs, WorkflowFinding{
+			File:    file,
+			Line:    n.Line,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	triggers := workflowTriggers(yamlValue(root, "on"))
+	_, prTarget := triggers["pull_request_target"]
+	untrusted := make([]string, 0)
+	for v := range triggers {
+		if _, ok := untrustedTriggers[v]; ok {
+			untrusted = append(untrusted, v)
+		}
+	}
+	sort.Strings(untrusted)
+
+	if p := yamlValue(root, "permissions"); p != nil && p.Value == "write-all" {
+		add(p, RuleWriteAllPermissions, "workflow grants write-all permissions")
+	}
+
+	jobs := yamlValue(root, "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return fs, nil
+	}
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		name, job := jobs.Content[i].Value, jobs.Content[i+1]
+		if job.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if p := yamlValue(job, "permissions"); p != nil && p.Value == "write-all" {
+			add(p, RuleWriteAllPermissions, "job %s grants write-all permissions", name)
+		}
+		// Jobs could call reusable workflows.
+		if u := yamlValue(job, "uses"); u != nil {
+			if ref, ok := unpinnedAction(u.Value); ok {
+				add(u, RuleUnpinnedAction, "job %s uses %s which is not pinned to a commit sha", name, ref)
+			}
+		}
+
+		steps := yamlValue(job, "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, step := range steps.Content {
+			if step.Kind != yaml.MappingNode {
+				continue
+			}
+
+			if u := yamlValue(step, "uses"); u != nil {
+				if ref, ok := unpinnedAction(u.Value); ok {
+					add(u, RuleUnpinnedAction, "job %s uses %s which is not pinned to a commit sha", name, ref)
+				}
+
+				if ref := yamlValue(yamlValue(step, "with"), "ref"); prTarget && ref != nil &&
+					strings.HasPrefix(u.Value, "actions/checkout@") && prHeadRegexp.MatchString(ref.Value) {
+					add(ref, RulePullRequestTargetCheckout,
+						"job %s checks out the pull request head in pull_request_target", name)
+				}
+			}
+
+			if r := yamlValue(step, "run"); r != nil && len(untrusted) > 0 && secretsRegexp.MatchString(r.Value) {
+				add(r, RuleSecretsInUntrustedRun, "job %s uses secrets in run step triggered by %s",
+					name, strings.Join(untrusted, ", "))
+			}
+		}
+	}
+
+	sort.SliceStable(fs, func(i, j int) bool {
+		return fs[i].Line < fs[j].Line
+	})
+	return fs, nil
+}
+
+// workflowTriggers returns events in the on section, which could be a
+// string, a list or a mapping.
+func workflowTriggers(n *yaml.Node) map[string]struct{} {
+	m := make(map[string]struct{})
+	if n == nil {
+		return m
+	}
+	switch n.Kind {
+	case yaml.ScalarNode:
+		m[n.Value] = struct{}{}
+	case yaml.SequenceNode:
+		for _, v := range n.Content {
+			m[v.Value] = struct{}{}
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = struct{}{}
+		}
+	}
+	return m
+}
+
+// unpinnedAction checks whether uses refers to a third-party action which is
+// not pinned to a commit sha, local actions are always trusted.
+func unpinnedAction(uses string) (string, bool) {
+	if strings.HasPrefix(uses, "./") {
+		return "", false
+	}
+	if strings.HasPrefix(uses, "docker://") {
+		return uses, !strings.Contains(uses, "@sha256:")
+	}
+
+	idx := strings.LastIndex(uses, "@")
+	if idx < 0 {
+		return uses, true
+	}
+	owner := uses[:idx]
+	if i := strings.Index(owner, "/"); i >= 0 {
+		owner = owner[:i]
+	}
+	if _, ok := firstPartyOwners[owner]; ok {
+		return "", false
+	}
+	return uses, !commitSHARegexp.MatchString(uses[idx+1:])
+}
+
+// yamlValue returns the value of key in a mapping node.
+func yamlValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
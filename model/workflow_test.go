@@ -0,0 +1,47 @@
+package model
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeWorkflow(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/workflows/risky.yml")
+	if err != nil {
+		t.Fatal("read file", err)
+	}
+
+	fs, err := AnalyzeWorkflow("risky.yml", data)
+	if err != nil {
+		t.Fatal("analyze workflow", err)
+	}
+
+	actual := make([]string, 0, len(fs))
+	for _, f := range fs {
+		actual = append(actual, f.String())
+	}
+	assert.Equal(t, []string{
+		"risky.yml:7: [write-all-permissions] workflow grants write-all permissions",
+		"risky.yml:12: [write-all-permissions] job test grants write-all permissions",
+		"risky.yml:16: [pull-request-target-checkout] job test checks out the pull request head in pull_request_target",
+		"risky.yml:17: [unpinned-action] job test uses golangci/golangci-lint-action@v2 which is not pinned to a commit sha",
+		"risky.yml:21: [secrets-in-untrusted-run] job test uses secrets in run step triggered by pull_request_target",
+		"risky.yml:24: [unpinned-action] job call uses beyondstorage/workflows/.github/workflows/ci.yml@master which is not pinned to a commit sha",
+	}, actual)
+}
+
+func TestAnalyzeWorkflow_Safe(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/workflows/safe.yml")
+	if err != nil {
+		t.Fatal("read file", err)
+	}
+
+	fs, err := AnalyzeWorkflow("safe.yml", data)
+	assert.NoError(t, err)
+	assert.Empty(t, fs)
+
+	_, err = AnalyzeWorkflow("invalid.yml", []byte("jobs: ["))
+	assert.Error(t, err)
+}
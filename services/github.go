@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"sort"
@@ -15,16 +16,6 @@ import (
 	"github.com/beyondstorage/go-community/model"
 )
 
-var (
-	permissionMap = map[model.Role]string{
-		model.RoleAdmin:       "admin",
-		model.RoleMaintainer:  "maintain",
-		model.RoleCommitter:   "push",
-		model.RoleReviewer:    "triage",
-		model.RoleContributor: "pull",
-	}
-)
-
 type Github struct {
 	owner string
 
@@ -48,9 +39,14 @@ func NewGithub(owner, token string) (g *Github, err error) {
 	return
 }
 
-func (g *Github) ListRepos(ctx context.Context) ([]string, error) {
+// ListRepos lists all repos in the org which pass the filter.
+func (g *Github) ListRepos(ctx context.Context, filter model.RepoFilter) ([]string, error) {
+	typ := "public"
+	if filter.IncludePrivate {
+		typ = "all"
+	}
 	opt := &github.RepositoryListByOrgOptions{
-		Type: "public",
+		Type: typ,
 		ListOptions: github.ListOptions{
 			PerPage: 100,
 		},
@@ -65,8 +61,15 @@ func (g *Github) ListRepos(ctx context.Context) ([]string, error) {
 		}
 
 		for _, v := range repos {
-			if v.GetArchived() {
-				g.logger.Info("ignore archived repo", zap.String("repo", v.GetName()))
+			private := v.GetPrivate() || v.GetVisibility() == "internal"
+			if !filter.Match(v.GetArchived(), private, v.GetFork(), v.GetIsTemplate(), v.Topics) {
+				g.logger.Info("ignore filtered repo",
+					zap.String("repo", v.GetName()),
+					zap.Bool("archived", v.GetArchived()),
+					zap.Bool("private", private),
+					zap.Bool("fork", v.GetFork()),
+					zap.Bool("template", v.GetIsTemplate()))
+				continue
 			}
 			rs = append(rs, v.GetName())
 		}
@@ -121,7 +124,7 @@ func (g *Github) SyncTeam(ctx context.Context, teams model.Teams, repos model.Re
 			}
 			_, err = g.client.Teams.AddTeamRepoBySlug(
 				ctx, g.owner, tn, g.owner, er,
-				&github.TeamAddTeamRepoOptions{Permission: permissionMap[t.Role]})
+				&github.TeamAddTeamRepoOptions{Permission: t.Role.Permission()})
 			if err != nil {
 				return fmt.Errorf("add team repo by slug: %w", err)
 			}
@@ -130,12 +133,23 @@ func (g *Github) SyncTeam(ctx context.Context, teams model.Teams, repos model.Re
 				zap.String("repo", er))
 		}
 
+		// Repos of parent teams are inherited by the team.
+		inheritedRepos := make(map[string]struct{})
+		for p, depth := t.Parent, 0; p != "" && depth < len(teams); p, depth = teams[p].Parent, depth+1 {
+			for _, v := range projects[teams[p].Project] {
+				inheritedRepos[v] = struct{}{}
+			}
+		}
+
 		// Delete githubRepos that in existRepos but not in expectRepos.
 		for er := range existRepos {
 			_, exist := expectRepos[er]
 			if exist {
 				continue
 			}
+			if _, ok := inheritedRepos[er]; ok {
+				continue
+			}
 			_, err = g.client.Teams.RemoveTeamRepoBySlug(
 				ctx, g.owner, tn, g.owner, er)
 			if err != nil {
@@ -146,46 +160,70 @@ func (g *Github) SyncTeam(ctx context.Context, teams model.Teams, repos model.Re
 				zap.String("repo", er))
 		}
 
+		emeritus := make(map[string]struct{})
+		for _, v := range t.Emeritus {
+			emeritus[v] = struct{}{}
+		}
+
 		expectMembers := make(map[string]struct{})
 		for _, v := range t.Members {
+			// Emeritus members don't have access anymore.
+			if _, ok := emeritus[v]; ok {
+				continue
+			}
 			expectMembers[v] = struct{}{}
 		}
 
+		// Members of child teams are listed in parent teams as well.
+		inherited := make(map[string]struct{})
+		for _, child := range teams.Descendants(tn) {
+			for _, v := range teams[child].Members {
+				inherited[v] = struct{}{}
+			}
+		}
+
+		members, err := g.listTeamMembers(ctx, tn, "all")
+		if err != nil {
+			g.logger.Error("list team members", zap.Error(err))
+			return err
+		}
 		existMembers := make(map[string]struct{})
-		teamopt := &github.TeamListTeamMembersOptions{
-			ListOptions: github.ListOptions{
-				PerPage: 100,
-			},
+		for _, v := range members {
+			existMembers[v] = struct{}{}
 		}
-		for {
-			rps, resp, err := g.client.Teams.ListTeamMembersBySlug(ctx, g.owner, tn, teamopt)
-			if err != nil {
-				g.logger.Error("list team members", zap.Error(err))
-				return err
-			}
-			for _, v := range rps {
-				existMembers[v.GetLogin()] = struct{}{}
-			}
-			if resp.NextPage == 0 {
-				break
-			}
-			teamopt.Page = resp.NextPage
+
+		maintainers, err := g.listTeamMembers(ctx, tn, "maintainer")
+		if err != nil {
+			g.logger.Error("list team maintainers", zap.Error(err))
+			return err
+		}
+		existMaintainers := make(map[string]struct{})
+		for _, v := range maintainers {
+			existMaintainers[v] = struct{}{}
 		}
 
-		// Add members that in expectMembers but not in existMembers.
+		// Add members that in expectMembers but not in existMembers, and
+		// correct the role of members in the team.
 		for m := range expectMembers {
+			role := "member"
+			if t.IsMaintainer(m) {
+				role = "maintainer"
+			}
+
 			_, exist := existMembers[m]
-			if exist {
+			_, maintainer := existMaintainers[m]
+			if exist && maintainer == (role == "maintainer") {
 				continue
 			}
 			_, _, err = g.client.Teams.AddTeamMembershipBySlug(
-				ctx, g.owner, tn, m, nil)
+				ctx, g.owner, tn, m, &github.TeamAddTeamMembershipOptions{Role: role})
 			if err != nil {
 				return fmt.Errorf("add team member by slug: %w", err)
 			}
 			g.logger.Info("Added member into team",
 				zap.String("team", tn),
-				zap.String("member", m))
+				zap.String("member", m),
+				zap.String("role", role))
 		}
 
 		// Delete members that in existMembers but not in expectMembers.
@@ -194,6 +232,9 @@ func (g *Github) SyncTeam(ctx context.Context, teams model.Teams, repos model.Re
 			if exist {
 				continue
 			}
+			if _, ok := inherited[m]; ok {
+				continue
+			}
 			_, err = g.client.Teams.RemoveTeamMembershipBySlug(
 				ctx, g.owner, tn, m)
 			if err != nil {
@@ -207,13 +248,18 @@ func (g *Github) SyncTeam(ctx context.Context, teams model.Teams, repos model.Re
 	return
 }
 
-func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos []string) (err error) {
+// SyncContributors invites contributors into the org following the policy,
+// and returns all contributors invited in this run.
+func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos []string, policy model.ContributorPolicy) (invited []model.Contributor, err error) {
 	// All members in team.
 	teamMembers := make(map[string]struct{})
 	for _, team := range teams {
 		for _, v := range team.Members {
 			teamMembers[v] = struct{}{}
 		}
+		for _, v := range team.Emeritus {
+			teamMembers[v] = struct{}{}
+		}
 	}
 
 	// All members in org.
@@ -227,7 +273,7 @@ func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos
 		rps, resp, err := g.client.Organizations.ListMembers(ctx, g.owner, opt)
 		if err != nil {
 			g.logger.Info("list team members", zap.Error(err))
-			return err
+			return nil, err
 		}
 		for _, v := range rps {
 			existMembers[v.GetLogin()] = struct{}{}
@@ -238,8 +284,26 @@ func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos
 		opt.Page = resp.NextPage
 	}
 
-	// A map about <Github Login> -> <Github ID>
-	expectMembers := make(map[string]int64)
+	// All pending invitations, expired ones will be canceled.
+	invitations, err := g.listPendingInvitations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for login, inv := range invitations {
+		if policy.InvitationExpire == 0 || inv.GetCreatedAt().After(policy.InvitationExpire.Since(time.Now())) {
+			continue
+		}
+		_, err = g.client.Organizations.RemoveOrgMembership(ctx, login, g.owner)
+		if err != nil {
+			return nil, fmt.Errorf("cancel invitation: %w", err)
+		}
+		g.logger.Info("canceled expired invitation",
+			zap.String("login", login),
+			zap.Time("created_at", inv.GetCreatedAt()))
+	}
+
+	// A map about <Github Login> -> Contributor
+	expectMembers := make(map[string]*model.Contributor)
 
 	// List all contributors
 	for _, repo := range repos {
@@ -251,10 +315,15 @@ func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos
 		for {
 			contributors, resp, err := g.client.Repositories.ListContributors(ctx, g.owner, repo, opt)
 			if err != nil {
-				return fmt.Errorf("list contributors: %w", err)
+				return nil, fmt.Errorf("list contributors: %w", err)
 			}
 			for _, v := range contributors {
-				expectMembers[v.GetLogin()] = v.GetID()
+				c, ok := expectMembers[v.GetLogin()]
+				if !ok {
+					c = &model.Contributor{Login: v.GetLogin(), ID: v.GetID()}
+					expectMembers[v.GetLogin()] = c
+				}
+				c.Contributions += v.GetContributions()
 			}
 			if resp.NextPage == 0 {
 				break
@@ -263,8 +332,9 @@ func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos
 		}
 	}
 
-	// Add all members that not in org and team.
-	for v, id := range expectMembers {
+	// Collect all contributors that not in org and team.
+	candidates := make([]model.Contributor, 0)
+	for v, c := range expectMembers {
 		_, exist := teamMembers[v]
 		if exist {
 			continue
@@ -273,49 +343,189 @@ func (g *Github) SyncContributors(ctx context.Context, teams model.Teams, repos
 		if exist {
 			continue
 		}
+		_, exist = invitations[v]
+		if exist {
+			continue
+		}
 		// We will ignore all bot account.
-		if g.isBot(v) {
+		if g.isBot(v) || policy.IsExcluded(v) {
 			continue
 		}
+		candidates = append(candidates, *c)
+	}
+	model.SortContributors(candidates)
+
+	teamID := []int64{}
+	if policy.Team != "" {
+		t, _, err := g.client.Teams.GetTeamBySlug(ctx, g.owner, policy.Team)
+		if err != nil {
+			return nil, fmt.Errorf("get team by slug %s: %w", policy.Team, err)
+		}
+		teamID = append(teamID, t.GetID())
+	}
+
+	for _, c := range candidates {
+		if policy.MaxInvites > 0 && len(invited) >= policy.MaxInvites {
+			g.logger.Info("reached max invites, stop inviting",
+				zap.Int("max_invites", policy.MaxInvites))
+			break
+		}
+
+		// Merged PRs are only counted for contributors without enough
+		// contributions, as it costs a search request.
+		if policy.MinContributions > 0 || policy.MinMergedPRs > 0 {
+			reached := policy.MinContributions > 0 && c.Contributions >= policy.MinContributions
+			if !reached && policy.MinMergedPRs > 0 {
+				n, err := g.countMergedPRs(ctx, c.Login, policy.Window.Since(time.Now()))
+				if err != nil {
+					return nil, err
+				}
+				reached = n >= policy.MinMergedPRs
+			}
+			if !reached {
+				g.logger.Debug("ignore contributor without enough contributions or merged prs",
+					zap.String("login", c.Login),
+					zap.Int("contributions", c.Contributions))
+				continue
+			}
+		}
+
 		_, _, err = g.client.Organizations.CreateOrgInvitation(ctx, g.owner, &github.CreateOrgInvitationOptions{
-			InviteeID: github.Int64(id),
+			InviteeID: github.Int64(c.ID),
 			Role:      github.String("direct_member"),
-			TeamID:    []int64{},
+			TeamID:    teamID,
 		})
 		if err != nil {
 			g.logger.Error("create invite for org",
-				zap.String("login", v),
-				zap.Int64("id", id))
-			return fmt.Errorf("create invite: %w", err)
+				zap.String("login", c.Login),
+				zap.Int64("id", c.ID))
+			return nil, fmt.Errorf("create invite: %w", err)
 		}
+		invited = append(invited, c)
+		g.logger.Info("invited contributor into org",
+			zap.String("login", c.Login),
+			zap.Int("contributions", c.Contributions))
 	}
-	return nil
+	return invited, nil
+}
+
+// ActionSyncResult is the result of syncing actions of a repo.
+type ActionSyncResult struct {
+	Repo        string
+	PullRequest string
+	// Unmanaged is true for repos without required actions, their workflows
+	// are only analyzed.
+	Unmanaged bool
+	// Findings are risky patterns in workflows of the repo.
+	Findings []model.WorkflowFinding
+	// Diff is the unified diff of all changed workflows.
+	Diff string
+}
+
+// SyncActionsOptions controls how actions are synced.
+type SyncActionsOptions struct {
+	// Pin pins refs of actions in local workflows to commit shas.
+	Pin bool
+	// DryRun only computes the diff without opening pull requests.
+	DryRun bool
 }
 
-func (g *Github) SyncActions(ctx context.Context, actionPath string, repos model.Repos) (err error) {
+// maxPRBodyDiff is the max length of diff in pull request body, github
+// rejects body longer than 65536 characters.
+const maxPRBodyDiff = 60000
+
+func (g *Github) SyncActions(ctx context.Context, actionPath string, repos model.Repos, opt SyncActionsOptions) (rs []ActionSyncResult, err error) {
+	var pinner *ActionPinner
+	if opt.Pin {
+		pinner = g.NewActionPinner()
+	}
+
+	// Local actions are keyed by name without extension.
+	localFiles, err := listLocalActions(actionPath)
+	if err != nil {
+		g.logger.Error("list local actions", zap.String("path", actionPath), zap.Error(err))
+		return nil, err
+	}
+	available := make([]string, 0, len(localFiles))
+	for name := range localFiles {
+		available = append(available, name)
+	}
+
+	// Local actions are shared by all repos, so we only read them once.
+	local := make(map[string][]byte)
+	readAction := func(ctx context.Context, name string) ([]byte, error) {
+		if bs, ok := local[name]; ok {
+			return bs, nil
+		}
+
+		actionFile := fmt.Sprintf("%s/%s", actionPath, localActionFile(localFiles, name))
+		bs, err := ioutil.ReadFile(actionFile)
+		if err != nil {
+			g.logger.Error("read local actions",
+				zap.String("path", actionFile), zap.Error(err))
+			return nil, err
+		}
+		if pinner != nil {
+			bs, err = pinner.Pin(ctx, bs)
+			if err != nil {
+				g.logger.Error("pin local actions",
+					zap.String("path", actionFile), zap.Error(err))
+				return nil, err
+			}
+		}
+		local[name] = bs
+		return bs, nil
+	}
+
 	for _, repo := range repos {
+		dc, err := g.listActions(ctx, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		result := ActionSyncResult{Repo: repo.Name}
+		remote := make(map[string][]byte)
+		for _, file := range dc {
+			content, err := file.GetContent()
+			if err != nil {
+				g.logger.Error("get repo content", zap.Error(err))
+				return nil, err
+			}
+			remote[file.GetPath()] = []byte(content)
+			fs, err := model.AnalyzeWorkflow(file.GetPath(), []byte(content))
+			if err != nil {
+				// Invalid workflows are reported by github, don't block the sync.
+				g.logger.Warn("analyze workflow",
+					zap.String("repo", repo.Name),
+					zap.String("path", file.GetPath()),
+					zap.Error(err))
+				continue
+			}
+			result.Findings = append(result.Findings, fs...)
+		}
+
+		// Workflows of all repos are analyzed, but only repos with required
+		// actions are synced.
 		if len(repo.Action.Required) == 0 {
 			g.logger.Info("repo doesn't have required actions, ignore",
 				zap.String("repo", repo.Name))
+			result.Unmanaged = true
+			rs = append(rs, result)
 			continue
 		}
 
-		dc, err := g.listActions(ctx, repo.Name)
-		if err != nil {
-			return err
-		}
-
 		// fileToAdd will be filled all required actions.
 		// If check passed, we remove it.
 		// If check failed, we update with old files sha.
 		fileToAdd := make(map[string]string)
-		for _, v := range repo.Action.Required {
+		for _, v := range repo.Action.RequiredActions(available) {
 			fileToAdd[v] = ""
 		}
+		// fileToRemove is keyed by path, as both .yml and .yaml could exist.
 		fileToRemove := make(map[string]string)
 
 		for _, file := range dc {
-			basename := strings.TrimSuffix(file.GetName(), ".yml")
+			basename := workflowName(file.GetName())
 
 			// We will keep all allowed actions untouched.
 			if repo.Action.IsAllowed(basename) {
@@ -325,20 +535,23 @@ func (g *Github) SyncActions(ctx context.Context, actionPath string, repos model
 				continue
 			}
 			// Check action required actions.
-			if repo.Action.IsRequired(basename) {
+			if _, ok := fileToAdd[basename]; ok {
+				// The file name is different from the local one, like
+				// .yaml instead of .yml, replace it with the desired one.
+				if file.GetName() != localActionFile(localFiles, basename) {
+					fileToRemove[file.GetPath()] = file.GetSHA()
+					continue
+				}
 				// Check file content.
 				ra, err := file.GetContent()
 				if err != nil {
 					g.logger.Error("get repo content", zap.Error(err))
-					return err
+					return nil, err
 				}
 				// Read local action files.
-				actionFile := fmt.Sprintf("%s/%s.yml", actionPath, basename)
-				bs, err := ioutil.ReadFile(actionFile)
+				bs, err := readAction(ctx, basename)
 				if err != nil {
-					g.logger.Error("read local actions",
-						zap.String("path", actionFile), zap.Error(err))
-					return err
+					return nil, err
 				}
 				if ra == string(bs) {
 					delete(fileToAdd, basename)
@@ -353,107 +566,102 @@ func (g *Github) SyncActions(ctx context.Context, actionPath string, repos model
 				}
 				continue
 			}
+			// Actions matching required patterns without local actions
+			// could not be synced, keep them.
+			if !repo.Action.IsRemovable(basename) {
+				g.logger.Warn("required action has no local action, keep",
+					zap.String("repo", repo.Name),
+					zap.String("name", basename))
+				continue
+			}
 			// Other actions should be removed.
-			fileToRemove[basename] = file.GetSHA()
+			fileToRemove[file.GetPath()] = file.GetSHA()
 		}
 
 		if len(fileToRemove) == 0 && len(fileToAdd) == 0 {
 			g.logger.Info("all actions are in sync, finished")
+			rs = append(rs, result)
 			continue
 		}
 
-		baseref, _, err := g.client.Git.GetRef(ctx, g.owner, repo.Name, "heads/master")
-		if err != nil {
-			g.logger.Error("get base ref", zap.Error(err))
-			return err
-		}
-
-		newBranch := fmt.Sprintf("sync-actions-%d", time.Now().Unix())
-		newref, _, err := g.client.Git.CreateRef(ctx, g.owner, repo.Name, &github.Reference{
-			Ref:    github.String("heads/" + newBranch),
-			Object: baseref.Object,
-		})
-		if err != nil {
-			g.logger.Error("create new ref", zap.Error(err))
-			return err
-		}
-
+		changes := make([]FileChange, 0, len(fileToRemove)+len(fileToAdd))
 		// Remove file that need to be removed.
-		for filename, sha := range fileToRemove {
-			sha := sha
-
-			filePath := fmt.Sprintf(".github/workflows/%s.yml", filename)
-			_, _, err = g.client.Repositories.DeleteFile(ctx, g.owner, repo.Name, filePath, &github.RepositoryContentFileOptions{
-				Message:   github.String("Delete not allowed file: " + filePath),
-				SHA:       &sha,
-				Branch:    github.String(newBranch),
-				Author:    g.getCommitter(),
-				Committer: g.getCommitter(),
+		for path, sha := range fileToRemove {
+			changes = append(changes, FileChange{
+				Path:    path,
+				SHA:     sha,
+				Message: "Delete not allowed file: " + path,
 			})
-			if err != nil {
-				g.logger.Error("delete file", zap.Error(err))
-				return err
-			}
-			g.logger.Info("remove not allowed files",
-				zap.String("repo", repo.Name),
-				zap.String("name", filePath))
 		}
 
 		for filename, sha := range fileToAdd {
-			sha := sha
-
-			actionFile := fmt.Sprintf("%s/%s.yml", actionPath, filename)
-			bs, err := ioutil.ReadFile(actionFile)
+			bs, err := readAction(ctx, filename)
 			if err != nil {
-				g.logger.Error("read local actions",
-					zap.String("path", actionFile), zap.Error(err))
-				return err
+				return nil, err
 			}
 
-			filePath := fmt.Sprintf(".github/workflows/%s.yml", filename)
-
-			rcf := &github.RepositoryContentFileOptions{
-				Message:   github.String("Add new file: " + filePath),
-				Content:   bs,
-				Branch:    github.String(newBranch),
-				Author:    g.getCommitter(),
-				Committer: g.getCommitter(),
-			}
-			if sha != "" {
-				// If sha is empty, we are creating files, or we are updating files.
-				rcf.SHA = github.String(sha)
-			}
+			changes = append(changes, FileChange{
+				Path:    ".github/workflows/" + localActionFile(localFiles, filename),
+				Content: bs,
+				SHA:     sha,
+			})
+		}
 
-			_, _, err = g.client.Repositories.CreateFile(ctx, g.owner, repo.Name, filePath, rcf)
+		sort.Slice(changes, func(i, j int) bool {
+			return changes[i].Path < changes[j].Path
+		})
+		for _, fc := range changes {
+			d, err := model.UnifiedDiff(fc.Path, remote[fc.Path], fc.Content)
 			if err != nil {
-				g.logger.Error("write new files",
-					zap.String("path", filePath),
-					zap.String("branch", newBranch),
-					zap.Error(err))
-				return err
+				return nil, fmt.Errorf("diff %s: %w", fc.Path, err)
 			}
+			result.Diff += d
+		}
 
-			g.logger.Info("add required files",
-				zap.String("repo", repo.Name),
-				zap.String("path", filePath))
+		if opt.DryRun {
+			rs = append(rs, result)
+			continue
 		}
 
-		_, _, err = g.client.PullRequests.Create(ctx, g.owner, repo.Name, &github.NewPullRequest{
-			Title:               github.String("ci: Sync github actions"),
-			Head:                newref.Ref,
-			Base:                baseref.Ref,
-			MaintainerCanModify: github.Bool(true),
-		})
+		result.PullRequest, err = g.createSyncPR(ctx, repo.Name, "sync-actions", "ci: Sync github actions",
+			actionSyncBody(result.Diff), changes)
 		if err != nil {
-			g.logger.Error("create pull request", zap.Error(err))
-			return err
+			return nil, err
 		}
+		rs = append(rs, result)
 	}
 
-	return nil
+	return rs, nil
 }
 
-func (g *Github) GenerateReportDataByRepo(ctx context.Context, org, repo string) (
+// actionSyncBody renders the pull request body of action sync with the diff.
+//
+// Long diff is truncated at a line boundary, and the fence is longer than
+// any backtick run in the diff so that workflows with code blocks don't
+// break it.
+func actionSyncBody(diff string) string {
+	if len(diff) > maxPRBodyDiff {
+		diff = diff[:strings.LastIndexByte(diff[:maxPRBodyDiff], '\n')+1] + "... (truncated)\n"
+	}
+
+	fence, run := 3, 0
+	for _, c := range diff {
+		if c != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run >= fence {
+			fence = run + 1
+		}
+	}
+	f := strings.Repeat("`", fence)
+	return fmt.Sprintf("Sync github actions with the community templates.\n\n%sdiff\n%s%s\n", f, diff, f)
+}
+
+// GenerateReportDataByRepo generates weekly report of the repo, registry is
+// used to render display names of users.
+func (g *Github) GenerateReportDataByRepo(ctx context.Context, org, repo string, registry model.Users) (
 	content string, users map[string]bool, stat model.Statistic, err error) {
 	users = make(map[string]bool)
 	events, err := g.listEvents(ctx, org, repo)
@@ -484,14 +692,14 @@ func (g *Github) GenerateReportDataByRepo(ctx context.Context, org, repo string)
 			switch e.GetAction() {
 			case "opened":
 				stat.CountIssueOpen()
-				b.WriteString(fmt.Sprintf("- [@%s] opened issue [%s](%s)\n",
-					v.GetActor().GetLogin(),
+				b.WriteString(fmt.Sprintf("- %s opened issue [%s](%s)\n",
+					registry.Mention(v.GetActor().GetLogin()),
 					e.GetIssue().GetTitle(),
 					e.GetIssue().GetHTMLURL()))
 			case "closed":
 				stat.CountIssueClose()
-				b.WriteString(fmt.Sprintf("- [@%s] closed issue [%s](%s)\n",
-					v.GetActor().GetLogin(),
+				b.WriteString(fmt.Sprintf("- %s closed issue [%s](%s)\n",
+					registry.Mention(v.GetActor().GetLogin()),
 					e.GetIssue().GetTitle(),
 					e.GetIssue().GetHTMLURL()))
 			default:
@@ -505,20 +713,20 @@ func (g *Github) GenerateReportDataByRepo(ctx context.Context, org, repo string)
 			switch e.GetAction() {
 			case "opened":
 				stat.CountPROpen()
-				b.WriteString(fmt.Sprintf("- [@%s] opened pull request [%s](%s)\n",
-					v.GetActor().GetLogin(),
+				b.WriteString(fmt.Sprintf("- %s opened pull request [%s](%s)\n",
+					registry.Mention(v.GetActor().GetLogin()),
 					e.GetPullRequest().GetTitle(),
 					e.GetPullRequest().GetHTMLURL()))
 			case "closed":
 				stat.CountPRClose()
 				if e.GetPullRequest().GetMerged() {
-					b.WriteString(fmt.Sprintf("- [@%s] merged pull request [%s](%s)\n",
-						v.GetActor().GetLogin(),
+					b.WriteString(fmt.Sprintf("- %s merged pull request [%s](%s)\n",
+						registry.Mention(v.GetActor().GetLogin()),
 						e.GetPullRequest().GetTitle(),
 						e.GetPullRequest().GetHTMLURL()))
 				} else {
-					b.WriteString(fmt.Sprintf("- [@%s] closed pull request [%s](%s)\n",
-						v.GetActor().GetLogin(),
+					b.WriteString(fmt.Sprintf("- %s closed pull request [%s](%s)\n",
+						registry.Mention(v.GetActor().GetLogin()),
 						e.GetPullRequest().GetTitle(),
 						e.GetPullRequest().GetHTMLURL()))
 				}
@@ -561,6 +769,79 @@ func (g *Github) CreateIssue(ctx context.Context, repo, title, content string) (
 	return issue.GetHTMLURL(), nil
 }
 
+// LatestMergedPR finds the most recent merged PR of the user in the org.
+//
+// Empty repo will be returned if the user doesn't have merged PRs.
+func (g *Github) LatestMergedPR(ctx context.Context, login string) (repo string, number int, url string, err error) {
+	// Search can't sort by merged time, so merged PRs are searched in
+	// growing windows and the latest closed one is picked, as merged PRs
+	// are closed when merged.
+	var pr *github.Issue
+	now := time.Now()
+	for _, days := range []int{30, 365, 0} {
+		query := fmt.Sprintf("org:%s is:pr is:merged author:%s", g.owner, login)
+		if days > 0 {
+			query += fmt.Sprintf(" merged:>=%s", now.AddDate(0, 0, -days).Format("2006-01-02"))
+		}
+		opt := &github.SearchOptions{
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		for {
+			result, resp, err := g.client.Search.Issues(ctx, query, opt)
+			if err != nil {
+				return "", 0, "", fmt.Errorf("search merged prs: %w", err)
+			}
+			for _, v := range result.Issues {
+				if pr == nil || v.GetClosedAt().After(pr.GetClosedAt()) {
+					pr = v
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+		if pr != nil {
+			break
+		}
+	}
+	if pr == nil {
+		return "", 0, "", nil
+	}
+
+	// RepositoryURL looks like https://api.github.com/repos/<owner>/<repo>
+	repoURL := pr.GetRepositoryURL()
+	repo = repoURL[strings.LastIndex(repoURL, "/")+1:]
+	return repo, pr.GetNumber(), pr.GetHTMLURL(), nil
+}
+
+func (g *Github) CreateComment(ctx context.Context, repo string, number int, content string) (commentURL string, err error) {
+	comment, _, err := g.client.Issues.CreateComment(ctx, g.owner, repo, number, &github.IssueComment{
+		Body: github.String(content),
+	})
+	if err != nil {
+		return
+	}
+	return comment.GetHTMLURL(), nil
+}
+
+// UserExists checks whether the login exists on github.
+func (g *Github) UserExists(ctx context.Context, login string) (bool, error) {
+	u, _, err := g.client.Users.Get(ctx, login)
+	if err != nil {
+		var e *github.ErrorResponse
+		if errors.As(err, &e) && e.Response.StatusCode == 404 {
+			return false, nil
+		}
+		return false, fmt.Errorf("get user %s: %w", login, err)
+	}
+	// Login is case-insensitive in github, but we require the same case.
+	return u.GetLogin() == login, nil
+}
+
+// listTeams lists all teams in the org keyed by slug.
 func (g *Github) listTeams(ctx context.Context) (teams map[string]*github.Team, err error) {
 	opt := &github.ListOptions{
 		PerPage: 100,
@@ -574,7 +855,7 @@ func (g *Github) listTeams(ctx context.Context) (teams map[string]*github.Team,
 		}
 		for _, v := range ts {
 			v := v
-			teams[v.GetName()] = v
+			teams[v.GetSlug()] = v
 		}
 		if resp.NextPage == 0 {
 			break
@@ -635,8 +916,11 @@ func (g *Github) listEvents(ctx context.Context, org, repo string) (es []*github
 	return
 }
 
-func (g *Github) listTeamMembers(ctx context.Context, team string) (users []string, err error) {
+// listTeamMembers lists members of the team with the role, which could be
+// "all", "member" or "maintainer".
+func (g *Github) listTeamMembers(ctx context.Context, team, role string) (users []string, err error) {
 	opt := &github.TeamListTeamMembersOptions{
+		Role: role,
 		ListOptions: github.ListOptions{
 			PerPage: 100,
 		},
@@ -645,7 +929,7 @@ func (g *Github) listTeamMembers(ctx context.Context, team string) (users []stri
 	for {
 		ts, resp, err := g.client.Teams.ListTeamMembersBySlug(ctx, g.owner, team, opt)
 		if err != nil {
-			return nil, fmt.Errorf("github list teams: %w", err)
+			return nil, fmt.Errorf("github list team members: %w", err)
 		}
 		for _, v := range ts {
 			users = append(users, v.GetLogin())
@@ -659,36 +943,119 @@ func (g *Github) listTeamMembers(ctx context.Context, team string) (users []stri
 	return users, nil
 }
 
+// listPendingInvitations returns pending invitations keyed by login,
+// invitations sent by email are ignored.
+func (g *Github) listPendingInvitations(ctx context.Context) (invitations map[string]*github.Invitation, err error) {
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	invitations = make(map[string]*github.Invitation)
+	for {
+		is, resp, err := g.client.Organizations.ListPendingOrgInvitations(ctx, g.owner, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list pending invitations: %w", err)
+		}
+		for _, v := range is {
+			v := v
+			if v.GetLogin() == "" {
+				continue
+			}
+			invitations[v.GetLogin()] = v
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return invitations, nil
+}
+
+// countMergedPRs counts PRs of the user merged in the org since the given
+// time, zero time means all time.
+func (g *Github) countMergedPRs(ctx context.Context, login string, since time.Time) (n int, err error) {
+	query := fmt.Sprintf("org:%s is:pr is:merged author:%s", g.owner, login)
+	if !since.IsZero() {
+		query += fmt.Sprintf(" merged:>=%s", since.Format("2006-01-02"))
+	}
+
+	result, _, err := g.client.Search.Issues(ctx, query, &github.SearchOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 1,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("search merged prs: %w", err)
+	}
+	return result.GetTotal(), nil
+}
+
+// setupTeams creates missing teams with parents first, and corrects the
+// parent, description and privacy of existing teams.
 func (g *Github) setupTeams(ctx context.Context, teams model.Teams) (err error) {
-	for teamName := range teams {
-		slug := teamName
+	names, err := teams.SortedNames()
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]int64)
+	for _, slug := range names {
+		t := teams[slug]
+
+		nt := github.NewTeam{
+			Name:        slug,
+			Description: github.String(t.Description),
+			Privacy:     github.String(t.GetPrivacy()),
+		}
+		if t.Parent != "" {
+			nt.ParentTeamID = github.Int64(ids[t.Parent])
+		}
 
-		_, resp, err := g.client.Teams.GetTeamBySlug(ctx, g.owner, slug)
+		gt, resp, err := g.client.Teams.GetTeamBySlug(ctx, g.owner, slug)
 		if err == nil {
-			// The team is exist, we can continue to setup next team.
+			ids[slug] = gt.GetID()
+			if gt.GetParent().GetSlug() == t.Parent &&
+				gt.GetDescription() == t.Description &&
+				gt.GetPrivacy() == t.GetPrivacy() {
+				continue
+			}
+
+			// The team has drifted from config, update it.
+			_, _, err = g.client.Teams.EditTeamBySlug(ctx, g.owner, slug, nt, t.Parent == "" && gt.Parent != nil)
+			if err != nil {
+				return fmt.Errorf("edit team slug %s: %v", slug, err)
+			}
+			g.logger.Info("Updated team",
+				zap.String("team", slug),
+				zap.String("parent", t.Parent),
+				zap.String("privacy", t.GetPrivacy()))
 			continue
 		}
 
-		if resp.StatusCode != 404 {
+		if resp == nil || resp.StatusCode != 404 {
 			// This error is not a valid github error, return directly.
 			return fmt.Errorf("get team by slug %s: %v", slug, err)
 		}
 
-		privacy := "closed" // open to all team members.
 		// Now we can handle the create team logic.
-		_, _, err = g.client.Teams.CreateTeam(ctx, g.owner, github.NewTeam{
-			Name:    slug,
-			Privacy: &privacy,
-		})
+		gt, _, err = g.client.Teams.CreateTeam(ctx, g.owner, nt)
 		if err != nil {
 			return fmt.Errorf("create team slug %s: %v", slug, err)
 		}
+		ids[slug] = gt.GetID()
+		g.logger.Info("Created team",
+			zap.String("team", slug),
+			zap.String("parent", t.Parent))
 	}
 	return nil
 }
 
 func (g *Github) listActions(ctx context.Context, repo string) (dc []*github.RepositoryContent, err error) {
 	_, idc, _, err := g.client.Repositories.GetContents(ctx, g.owner, repo, ".github/workflows", nil)
+	// Repos without workflows don't have the folder.
+	if err != nil && isNotFound(err) {
+		return nil, nil
+	}
 	if err != nil {
 		g.logger.Error("get folder",
 			zap.String("repo", repo),
@@ -699,7 +1066,7 @@ func (g *Github) listActions(ctx context.Context, repo string) (dc []*github.Rep
 
 	dc = make([]*github.RepositoryContent, 0)
 	for _, file := range idc {
-		if file.GetType() != "file" || !strings.HasSuffix(file.GetName(), ".yml") {
+		if file.GetType() != "file" || !isWorkflowFile(file.GetName()) {
 			continue
 		}
 		fc, _, _, err := g.client.Repositories.GetContents(ctx, g.owner, repo, file.GetPath(), nil)
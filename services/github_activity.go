@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// triageEvents are issue events which count as issue triage.
+var triageEvents = map[string]struct{}{
+	"labeled":             {},
+	"unlabeled":           {},
+	"closed":              {},
+	"reopened":            {},
+	"assigned":            {},
+	"milestoned":          {},
+	"marked_as_duplicate": {},
+}
+
+// RepoActivities collects commits, merged PRs, reviews and issue triage of
+// users in the repo since the given time.
+func (g *Github) RepoActivities(ctx context.Context, repo string, since time.Time) (as model.Activities, err error) {
+	as = make(model.Activities)
+
+	copt := &github.CommitsListOptions{
+		Since: since,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		commits, resp, err := g.client.Repositories.ListCommits(ctx, g.owner, repo, copt)
+		if err != nil {
+			var e *github.ErrorResponse
+			// Empty repo will return 409 conflict.
+			if errors.As(err, &e) && e.Response.StatusCode == 409 {
+				break
+			}
+			g.logger.Error("list commits", zap.String("repo", repo), zap.Error(err))
+			return nil, err
+		}
+		for _, v := range commits {
+			// Commits without github author can't be counted.
+			author := v.GetAuthor().GetLogin()
+			if author == "" || g.isBot(author) {
+				continue
+			}
+			a := as.Get(author)
+			a.Commits = append(a.Commits, v.GetHTMLURL())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		copt.Page = resp.NextPage
+	}
+
+	opt := &github.PullRequestListOptions{
+		State:     "all",
+		Sort:      "updated",
+		Direction: "desc",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		prs, resp, err := g.client.PullRequests.List(ctx, g.owner, repo, opt)
+		if err != nil {
+			g.logger.Error("list pull requests", zap.String("repo", repo), zap.Error(err))
+			return nil, err
+		}
+
+		done := false
+		for _, pr := range prs {
+			if pr.GetUpdatedAt().Before(since) {
+				done = true
+				break
+			}
+
+			author := pr.GetUser().GetLogin()
+			if pr.MergedAt != nil && !pr.GetMergedAt().Before(since) && !g.isBot(author) {
+				a := as.Get(author)
+				a.MergedPRs = append(a.MergedPRs, pr.GetHTMLURL())
+			}
+
+			reviews, err := g.listReviews(ctx, repo, pr.GetNumber())
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range reviews {
+				reviewer := v.GetUser().GetLogin()
+				if reviewer == author || g.isBot(reviewer) || v.GetSubmittedAt().Before(since) {
+					continue
+				}
+				a := as.Get(reviewer)
+				a.Reviews = append(a.Reviews, v.GetHTMLURL())
+			}
+		}
+
+		if done || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	// Issue events are listed from the newest one.
+	triaged := make(map[string]struct{})
+	eopt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		events, resp, err := g.client.Issues.ListRepositoryEvents(ctx, g.owner, repo, eopt)
+		if err != nil {
+			g.logger.Error("list issue events", zap.String("repo", repo), zap.Error(err))
+			return nil, err
+		}
+
+		done := false
+		for _, e := range events {
+			if e.GetCreatedAt().Before(since) {
+				done = true
+				break
+			}
+			if _, ok := triageEvents[e.GetEvent()]; !ok {
+				continue
+			}
+
+			issue := e.GetIssue()
+			actor := e.GetActor().GetLogin()
+			if issue.IsPullRequest() || actor == issue.GetUser().GetLogin() || g.isBot(actor) {
+				continue
+			}
+
+			// Only count once for every issue.
+			key := fmt.Sprintf("%s#%s", actor, issue.GetHTMLURL())
+			if _, ok := triaged[key]; ok {
+				continue
+			}
+			triaged[key] = struct{}{}
+
+			a := as.Get(actor)
+			a.Triages = append(a.Triages, issue.GetHTMLURL())
+		}
+
+		if done || resp.NextPage == 0 {
+			break
+		}
+		eopt.Page = resp.NextPage
+	}
+
+	return as, nil
+}
+
+func (g *Github) listReviews(ctx context.Context, repo string, number int) (reviews []*github.PullRequestReview, err error) {
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	for {
+		rs, resp, err := g.client.PullRequests.ListReviews(ctx, g.owner, repo, number, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list reviews: %w", err)
+		}
+		reviews = append(reviews, rs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return reviews, nil
+}
+
+// hasReview checks whether the pull request has been reviewed by anyone
+// other than its author, reviews of bots like codecov don't count.
+func (g *Github) hasReview(ctx context.Context, repo string, pr *github.PullRequest) (bool, error) {
+	reviews, err := g.listReviews(ctx, repo, pr.GetNumber())
+	if err != nil {
+		return false, err
+	}
+	for _, v := range reviews {
+		login := v.GetUser().GetLogin()
+		// Github apps like codecov are not in our bot list.
+		bot := g.isBot(login) || v.GetUser().GetType() == "Bot"
+		if login != pr.GetUser().GetLogin() && !bot {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LastActivity finds the most recent commit, review and comment of the user
+// in given repos.
+func (g *Github) LastActivity(ctx context.Context, login string, repos []string) (last model.LastActivity, err error) {
+	allowed := make(map[string]struct{})
+	for _, v := range repos {
+		allowed[v] = struct{}{}
+	}
+
+	for _, repo := range repos {
+		commits, _, err := g.client.Repositories.ListCommits(ctx, g.owner, repo, &github.CommitsListOptions{
+			Author: login,
+			ListOptions: github.ListOptions{
+				PerPage: 1,
+			},
+		})
+		if err != nil {
+			var e *github.ErrorResponse
+			// Empty repo will return 409 conflict.
+			if errors.As(err, &e) && e.Response.StatusCode == 409 {
+				continue
+			}
+			return last, fmt.Errorf("list commits: %w", err)
+		}
+		if len(commits) > 0 {
+			last.Update("commit", commits[0].GetCommit().GetCommitter().GetDate(), commits[0].GetHTMLURL())
+		}
+	}
+
+	// Search can't be limited to many repos, so we search the whole org
+	// from the most recently updated. The user's own review or comment is
+	// never newer than the update of its issue, so we stop once issues are
+	// older than the activity found.
+	queries := map[string]string{
+		"review":  fmt.Sprintf("org:%s is:pr reviewed-by:%s", g.owner, login),
+		"comment": fmt.Sprintf("org:%s commenter:%s", g.owner, login),
+	}
+	for kind, query := range queries {
+		var found time.Time
+		opt := &github.SearchOptions{
+			Sort:  "updated",
+			Order: "desc",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+	search:
+		for {
+			result, resp, err := g.client.Search.Issues(ctx, query, opt)
+			if err != nil {
+				return last, fmt.Errorf("search %s: %w", kind, err)
+			}
+			for _, v := range result.Issues {
+				if !v.GetUpdatedAt().After(found) {
+					break search
+				}
+				repoURL := v.GetRepositoryURL()
+				repo := repoURL[strings.LastIndex(repoURL, "/")+1:]
+				if _, ok := allowed[repo]; !ok {
+					continue
+				}
+
+				at, url, err := g.userActivity(ctx, kind, repo, v.GetNumber(), login)
+				if err != nil {
+					return last, err
+				}
+				if at.After(found) {
+					found = at
+					last.Update(kind, at, url)
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+	}
+	return last, nil
+}
+
+// userActivity returns the time and link of the latest review or comment
+// of the user on the issue or pull request.
+func (g *Github) userActivity(ctx context.Context, kind, repo string, number int, login string) (at time.Time, url string, err error) {
+	if kind == "review" {
+		reviews, err := g.listReviews(ctx, repo, number)
+		if err != nil {
+			return at, "", err
+		}
+		for _, v := range reviews {
+			if v.GetUser().GetLogin() == login && v.GetSubmittedAt().After(at) {
+				at, url = v.GetSubmittedAt(), v.GetHTMLURL()
+			}
+		}
+		return at, url, nil
+	}
+
+	opt := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		cs, resp, err := g.client.Issues.ListComments(ctx, g.owner, repo, number, opt)
+		if err != nil {
+			return at, "", fmt.Errorf("github list comments of %s#%d: %w", repo, number, err)
+		}
+		for _, v := range cs {
+			if v.GetUser().GetLogin() == login && v.GetCreatedAt().After(at) {
+				at, url = v.GetCreatedAt(), v.GetHTMLURL()
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return at, url, nil
+}
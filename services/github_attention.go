@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// AttentionItems lists issues opened before since without any response from
+// responders, and pull requests opened before since without any review.
+//
+// Issues opened by responders and items opened by bots are skipped, draft
+// pull requests don't need review yet.
+func (g *Github) AttentionItems(ctx context.Context, repo string, since time.Time, isResponder func(login string) bool) (items []model.AttentionItem, err error) {
+	issues, err := g.listOpenIssues(ctx, repo, "created")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		author := issue.GetUser().GetLogin()
+		if issue.IsPullRequest() || issue.GetCreatedAt().After(since) ||
+			g.isBot(author) || isResponder(author) {
+			continue
+		}
+
+		responded, err := g.hasResponse(ctx, repo, issue.GetNumber(), author, isResponder)
+		if err != nil {
+			return nil, err
+		}
+		if responded {
+			continue
+		}
+		items = append(items, model.AttentionItem{
+			Repo:      repo,
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			URL:       issue.GetHTMLURL(),
+			Author:    author,
+			CreatedAt: issue.GetCreatedAt(),
+		})
+	}
+
+	prs, err := g.listOpenPRs(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		author := pr.GetUser().GetLogin()
+		if pr.GetDraft() || pr.GetCreatedAt().After(since) || g.isBot(author) {
+			continue
+		}
+
+		reviewed, err := g.hasReview(ctx, repo, pr)
+		if err != nil {
+			return nil, err
+		}
+		if reviewed {
+			continue
+		}
+		items = append(items, model.AttentionItem{
+			Repo:        repo,
+			Number:      pr.GetNumber(),
+			Title:       pr.GetTitle(),
+			URL:         pr.GetHTMLURL(),
+			Author:      author,
+			PullRequest: true,
+			CreatedAt:   pr.GetCreatedAt(),
+		})
+	}
+	return items, nil
+}
+
+// hasResponse checks whether any responder other than the author has
+// commented on the issue.
+func (g *Github) hasResponse(ctx context.Context, repo string, number int, author string, isResponder func(login string) bool) (bool, error) {
+	opt := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		cs, resp, err := g.client.Issues.ListComments(ctx, g.owner, repo, number, opt)
+		if err != nil {
+			return false, fmt.Errorf("github list comments of %s#%d: %w", repo, number, err)
+		}
+		for _, v := range cs {
+			login := v.GetUser().GetLogin()
+			if login != author && isResponder(login) {
+				return true, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return false, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// permissionLadder is github repo permissions from the highest one.
+var permissionLadder = []string{"admin", "maintain", "push", "triage", "pull"}
+
+// RepoAccess lists every user with access to repos and how they got it,
+// keyed by repo name.
+func (g *Github) RepoAccess(ctx context.Context, repos []string) (access map[string][]model.Access, err error) {
+	admins, err := g.listOrgMembers(ctx, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	outside := make(map[string]struct{})
+	oopt := &github.ListOutsideCollaboratorsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		us, resp, err := g.client.Organizations.ListOutsideCollaborators(ctx, g.owner, oopt)
+		if err != nil {
+			return nil, fmt.Errorf("github list outside collaborators: %w", err)
+		}
+		for _, v := range us {
+			outside[v.GetLogin()] = struct{}{}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		oopt.Page = resp.NextPage
+	}
+
+	// Members of teams are shared by repos.
+	teamMembers := make(map[string][]string)
+
+	access = make(map[string][]model.Access)
+	for _, repo := range repos {
+		as := make([]model.Access, 0)
+		for _, v := range admins {
+			as = append(as, model.Access{
+				Login:      v,
+				Source:     model.AccessOrgRole,
+				Permission: "admin",
+			})
+		}
+
+		topt := &github.ListOptions{
+			PerPage: 100,
+		}
+		for {
+			ts, resp, err := g.client.Repositories.ListTeams(ctx, g.owner, repo, topt)
+			if err != nil {
+				g.logger.Error("list repo teams", zap.String("repo", repo), zap.Error(err))
+				return nil, err
+			}
+			for _, t := range ts {
+				members, ok := teamMembers[t.GetSlug()]
+				if !ok {
+					members, err = g.listTeamMembers(ctx, t.GetSlug(), "all")
+					if err != nil {
+						return nil, err
+					}
+					teamMembers[t.GetSlug()] = members
+				}
+				for _, v := range members {
+					as = append(as, model.Access{
+						Login:      v,
+						Source:     model.AccessTeam,
+						Team:       t.GetSlug(),
+						Permission: t.GetPermission(),
+					})
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			topt.Page = resp.NextPage
+		}
+
+		copt := &github.ListCollaboratorsOptions{
+			Affiliation: "direct",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		}
+		for {
+			us, resp, err := g.client.Repositories.ListCollaborators(ctx, g.owner, repo, copt)
+			if err != nil {
+				g.logger.Error("list collaborators", zap.String("repo", repo), zap.Error(err))
+				return nil, err
+			}
+			for _, v := range us {
+				source := model.AccessDirect
+				if _, ok := outside[v.GetLogin()]; ok {
+					source = model.AccessOutside
+				}
+				as = append(as, model.Access{
+					Login:      v.GetLogin(),
+					Source:     source,
+					Permission: highestPermission(v.GetPermissions()),
+				})
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			copt.Page = resp.NextPage
+		}
+
+		access[repo] = as
+	}
+	return access, nil
+}
+
+// RemoveCollaborator removes the direct access of the user to the repo.
+func (g *Github) RemoveCollaborator(ctx context.Context, repo, login string) (err error) {
+	_, err = g.client.Repositories.RemoveCollaborator(ctx, g.owner, repo, login)
+	if err != nil {
+		return fmt.Errorf("remove collaborator: %w", err)
+	}
+	g.logger.Info("Removed collaborator",
+		zap.String("repo", repo),
+		zap.String("login", login))
+	return nil
+}
+
+// listOrgMembers lists members of the org with the role, which could be
+// "all", "admin" or "member".
+func (g *Github) listOrgMembers(ctx context.Context, role string) (users []string, err error) {
+	opt := &github.ListMembersOptions{
+		Role: role,
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	for {
+		us, resp, err := g.client.Organizations.ListMembers(ctx, g.owner, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list org members: %w", err)
+		}
+		for _, v := range us {
+			users = append(users, v.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return users, nil
+}
+
+func highestPermission(ps map[string]bool) string {
+	for _, v := range permissionLadder {
+		if ps[v] {
+			return v
+		}
+	}
+	return ""
+}
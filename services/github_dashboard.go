@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v35/github"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// RepoHealth collects open issues and pull requests, the oldest unreviewed
+// pull request, the last release and CI status of the default branch.
+//
+// Action sync status and teams are not known by github, they are left empty.
+func (g *Github) RepoHealth(ctx context.Context, repo string) (h model.RepoHealth, err error) {
+	h.Name = repo
+
+	r, _, err := g.client.Repositories.Get(ctx, g.owner, repo)
+	if err != nil {
+		return h, fmt.Errorf("github get repo %s: %w", repo, err)
+	}
+
+	prs, err := g.listOpenPRs(ctx, repo)
+	if err != nil {
+		return h, err
+	}
+	h.OpenPRs = len(prs)
+	// Open issues count of github includes pull requests.
+	h.OpenIssues = r.GetOpenIssuesCount() - len(prs)
+
+	// Pull requests are sorted by created time, so the first unreviewed
+	// one is the oldest.
+	for _, pr := range prs {
+		if pr.GetDraft() || g.isBot(pr.GetUser().GetLogin()) {
+			continue
+		}
+		reviewed, err := g.hasReview(ctx, repo, pr)
+		if err != nil {
+			return h, err
+		}
+		if reviewed {
+			continue
+		}
+		h.OldestUnreviewedPR = &model.PullRequestRef{
+			Number:    pr.GetNumber(),
+			Title:     pr.GetTitle(),
+			URL:       pr.GetHTMLURL(),
+			CreatedAt: pr.GetCreatedAt(),
+		}
+		break
+	}
+
+	release, _, err := g.client.Repositories.GetLatestRelease(ctx, g.owner, repo)
+	if err != nil && !isNotFound(err) {
+		return h, fmt.Errorf("github get latest release of %s: %w", repo, err)
+	}
+	if err == nil {
+		h.LastRelease = &model.ReleaseRef{
+			Tag:         release.GetTagName(),
+			URL:         release.GetHTMLURL(),
+			PublishedAt: release.GetPublishedAt().Time,
+		}
+	}
+
+	h.CIStatus, err = g.ciStatus(ctx, repo, r.GetDefaultBranch())
+	if err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+// ciStatus summarizes check runs of the ref, commit statuses are used for
+// repos without check runs.
+func (g *Github) ciStatus(ctx context.Context, repo, ref string) (string, error) {
+	opt := &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	status, total := model.CIStatusSuccess, 0
+	for {
+		rs, resp, err := g.client.Checks.ListCheckRunsForRef(ctx, g.owner, repo, ref, opt)
+		if err != nil {
+			// Empty repo doesn't have the default branch.
+			if isNotFound(err) {
+				return "", nil
+			}
+			return "", fmt.Errorf("github list check runs of %s: %w", repo, err)
+		}
+		for _, v := range rs.CheckRuns {
+			total++
+			switch {
+			case v.GetStatus() != "completed":
+				if status == model.CIStatusSuccess {
+					status = model.CIStatusPending
+				}
+			case v.GetConclusion() == "failure", v.GetConclusion() == "timed_out",
+				v.GetConclusion() == "cancelled", v.GetConclusion() == "action_required":
+				status = model.CIStatusFailure
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if total > 0 {
+		return status, nil
+	}
+
+	cs, _, err := g.client.Repositories.GetCombinedStatus(ctx, g.owner, repo, ref, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("github get combined status of %s: %w", repo, err)
+	}
+	if cs.GetTotalCount() == 0 {
+		return "", nil
+	}
+	switch cs.GetState() {
+	case "success":
+		return model.CIStatusSuccess, nil
+	case "pending":
+		return model.CIStatusPending, nil
+	default:
+		return model.CIStatusFailure, nil
+	}
+}
+
+// listOpenIssues lists open issues and pull requests sorted by sort, which
+// is "created" or "updated", from the oldest.
+func (g *Github) listOpenIssues(ctx context.Context, repo, sort string) (issues []*github.Issue, err error) {
+	opt := &github.IssueListByRepoOptions{
+		State:     "open",
+		Sort:      sort,
+		Direction: "asc",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		is, resp, err := g.client.Issues.ListByRepo(ctx, g.owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list issues of %s: %w", repo, err)
+		}
+		issues = append(issues, is...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return issues, nil
+}
+
+// listOpenPRs lists open pull requests from the oldest.
+func (g *Github) listOpenPRs(ctx context.Context, repo string) (prs []*github.PullRequest, err error) {
+	opt := &github.PullRequestListOptions{
+		State:     "open",
+		Sort:      "created",
+		Direction: "asc",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	for {
+		ps, resp, err := g.client.PullRequests.List(ctx, g.owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list pull requests of %s: %w", repo, err)
+		}
+		prs = append(prs, ps...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return prs, nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bytes"
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+const (
+	maintainersPath = "MAINTAINERS.md"
+	codeownersPath  = ".github/CODEOWNERS"
+)
+
+// SyncOwners keeps MAINTAINERS.md and CODEOWNERS of repos in sync with
+// teams, a pull request will be opened for every repo out of sync.
+//
+// Changed files are returned by repo, no pull request is opened in dry run.
+func (g *Github) SyncOwners(ctx context.Context, teams model.Teams, repos model.Repos, users model.Users, dryRun bool) (changed map[string][]string, err error) {
+	changed = make(map[string][]string)
+
+	for _, repo := range repos {
+		if len(repo.Project) == 0 {
+			continue
+		}
+
+		expected := map[string]string{
+			maintainersPath: model.RenderMaintainers(repo, teams, users),
+			codeownersPath:  model.RenderCodeowners(g.owner, repo, teams),
+		}
+
+		changes := make([]FileChange, 0, len(expected))
+		for _, path := range []string{maintainersPath, codeownersPath} {
+			content, sha, err := g.GetFile(ctx, repo.Name, path)
+			if err != nil {
+				g.logger.Error("get file", zap.String("repo", repo.Name), zap.String("path", path), zap.Error(err))
+				return nil, err
+			}
+			if bytes.Equal(content, []byte(expected[path])) {
+				continue
+			}
+			changes = append(changes, FileChange{
+				Path:    path,
+				Content: []byte(expected[path]),
+				SHA:     sha,
+				Message: "docs: Sync " + path + " with teams.toml",
+			})
+			changed[repo.Name] = append(changed[repo.Name], path)
+		}
+		if len(changes) == 0 || dryRun {
+			continue
+		}
+
+		prURL, err := g.createSyncPR(ctx, repo.Name, "sync-owners",
+			"docs: Sync maintainers and code owners",
+			"MAINTAINERS.md and CODEOWNERS are generated from teams.toml.", changes)
+		if err != nil {
+			return nil, err
+		}
+		g.logger.Info("sync owners", zap.String("repo", repo.Name), zap.String("pr", prURL))
+	}
+	return changed, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// ActionPinner pins refs of actions in workflows to commit shas, resolved
+// refs and tags are cached so that every action is resolved once.
+type ActionPinner struct {
+	g *Github
+
+	shas map[string]string
+	// tags is tag name to commit sha, keyed by owner/repo.
+	tags map[string]map[string]string
+}
+
+func (g *Github) NewActionPinner() *ActionPinner {
+	return &ActionPinner{
+		g:    g,
+		shas: make(map[string]string),
+		tags: make(map[string]map[string]string),
+	}
+}
+
+// Pin pins all refs which are not commit shas, the original ref is kept as
+// a trailing comment.
+func (p *ActionPinner) Pin(ctx context.Context, data []byte) ([]byte, error) {
+	return model.RewriteActionRefs(data, func(r model.ActionRef) (model.ActionRef, error) {
+		if r.Pinned() {
+			return r, nil
+		}
+
+		owner, repo := r.Repo()
+		key := fmt.Sprintf("%s/%s@%s", owner, repo, r.Ref)
+		sha, ok := p.shas[key]
+		if !ok {
+			var err error
+			sha, _, err = p.g.client.Repositories.GetCommitSHA1(ctx, owner, repo, r.Ref, "")
+			if err != nil {
+				return r, fmt.Errorf("resolve %s: %w", key, err)
+			}
+			p.shas[key] = sha
+		}
+		return model.ActionRef{Action: r.Action, Ref: sha, Tag: r.Ref}, nil
+	})
+}
+
+// Bump moves pinned refs and version tags to the latest tag, see
+// model.BumpActionRef. Tags which have been moved to another commit like
+// "v2" will be resolved again for pinned refs.
+func (p *ActionPinner) Bump(ctx context.Context, data []byte, major bool) ([]byte, error) {
+	return model.RewriteActionRefs(data, func(r model.ActionRef) (model.ActionRef, error) {
+		if !r.Bumpable() {
+			return r, nil
+		}
+
+		owner, repo := r.Repo()
+		tags, err := p.listTags(ctx, owner, repo)
+		if err != nil {
+			return r, err
+		}
+
+		x, ok := model.BumpActionRef(r, tags, major)
+		if !ok {
+			p.g.logger.Warn("tag of pinned action is not found",
+				zap.String("action", r.Action),
+				zap.String("tag", r.Tag))
+			return r, nil
+		}
+		return x, nil
+	})
+}
+
+func (p *ActionPinner) listTags(ctx context.Context, owner, repo string) (map[string]string, error) {
+	key := fmt.Sprintf("%s/%s", owner, repo)
+	if tags, ok := p.tags[key]; ok {
+		return tags, nil
+	}
+
+	tags := make(map[string]string)
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		ts, resp, err := p.g.client.Repositories.ListTags(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list tags of %s: %w", key, err)
+		}
+		for _, v := range ts {
+			tags[v.GetName()] = v.GetCommit().GetSHA()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	p.tags[key] = tags
+	return tags, nil
+}
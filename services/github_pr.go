@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+)
+
+// FileChange is a file to be changed in a sync pull request.
+type FileChange struct {
+	Path string
+	// Content is the new content of the file, nil means delete the file.
+	Content []byte
+	// SHA is the blob sha of the existing file, empty means the file is new.
+	SHA string
+	// Message is the commit message of the change, a generic message like
+	// "Update file: <path>" is used if empty.
+	Message string
+}
+
+// createSyncPR creates a branch from the default branch, commits all changes
+// on it and opens a pull request against the default branch.
+func (g *Github) createSyncPR(ctx context.Context, repo, branchPrefix, title, body string, changes []FileChange) (prURL string, err error) {
+	r, _, err := g.client.Repositories.Get(ctx, g.owner, repo)
+	if err != nil {
+		g.logger.Error("get repo", zap.Error(err))
+		return "", err
+	}
+
+	baseref, _, err := g.client.Git.GetRef(ctx, g.owner, repo, "heads/"+r.GetDefaultBranch())
+	if err != nil {
+		g.logger.Error("get base ref", zap.Error(err))
+		return "", err
+	}
+
+	newBranch := fmt.Sprintf("%s-%d", branchPrefix, time.Now().Unix())
+	newref, _, err := g.client.Git.CreateRef(ctx, g.owner, repo, &github.Reference{
+		Ref:    github.String("heads/" + newBranch),
+		Object: baseref.Object,
+	})
+	if err != nil {
+		g.logger.Error("create new ref", zap.Error(err))
+		return "", err
+	}
+
+	for _, fc := range changes {
+		message := fc.Message
+		if fc.Content == nil {
+			if message == "" {
+				message = "Delete file: " + fc.Path
+			}
+			_, _, err = g.client.Repositories.DeleteFile(ctx, g.owner, repo, fc.Path, &github.RepositoryContentFileOptions{
+				Message:   github.String(message),
+				SHA:       github.String(fc.SHA),
+				Branch:    github.String(newBranch),
+				Author:    g.getCommitter(),
+				Committer: g.getCommitter(),
+			})
+			if err != nil {
+				g.logger.Error("delete file", zap.Error(err))
+				return "", err
+			}
+			g.logger.Info("delete file",
+				zap.String("repo", repo),
+				zap.String("name", fc.Path))
+			continue
+		}
+
+		if message == "" {
+			message = "Add new file: " + fc.Path
+			if fc.SHA != "" {
+				message = "Update file: " + fc.Path
+			}
+		}
+		rcf := &github.RepositoryContentFileOptions{
+			Message:   github.String(message),
+			Content:   fc.Content,
+			Branch:    github.String(newBranch),
+			Author:    g.getCommitter(),
+			Committer: g.getCommitter(),
+		}
+		if fc.SHA != "" {
+			// If sha is empty, we are creating files, or we are updating files.
+			rcf.SHA = github.String(fc.SHA)
+		}
+
+		_, _, err = g.client.Repositories.CreateFile(ctx, g.owner, repo, fc.Path, rcf)
+		if err != nil {
+			g.logger.Error("write new files",
+				zap.String("path", fc.Path),
+				zap.String("branch", newBranch),
+				zap.Error(err))
+			return "", err
+		}
+		g.logger.Info("write file",
+			zap.String("repo", repo),
+			zap.String("path", fc.Path))
+	}
+
+	pr, _, err := g.client.PullRequests.Create(ctx, g.owner, repo, &github.NewPullRequest{
+		Title:               github.String(title),
+		Head:                newref.Ref,
+		Base:                baseref.Ref,
+		Body:                github.String(body),
+		MaintainerCanModify: github.Bool(true),
+	})
+	if err != nil {
+		g.logger.Error("create pull request", zap.Error(err))
+		return "", err
+	}
+	return pr.GetHTMLURL(), nil
+}
+
+// GetFile returns the content and blob sha of a file in the default branch,
+// nil content will be returned if the file doesn't exist.
+func (g *Github) GetFile(ctx context.Context, repo, path string) (content []byte, sha string, err error) {
+	fc, _, _, err := g.client.Repositories.GetContents(ctx, g.owner, repo, path, nil)
+	if err != nil {
+		var e *github.ErrorResponse
+		if errors.As(err, &e) && e.Response.StatusCode == 404 {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("get contents %s: %w", path, err)
+	}
+	if fc == nil {
+		return nil, "", fmt.Errorf("%s is not a file", path)
+	}
+
+	s, err := fc.GetContent()
+	if err != nil {
+		return nil, "", fmt.Errorf("decode contents %s: %w", path, err)
+	}
+	return []byte(s), fc.GetSHA(), nil
+}
+
+// ProposeChanges opens a pull request with the changes.
+func (g *Github) ProposeChanges(ctx context.Context, repo, title, body string, changes []FileChange) (prURL string, err error) {
+	return g.createSyncPR(ctx, repo, "community", title, body, changes)
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// SecretSyncResult is the changes of actions secrets and variables of a repo.
+//
+// Extra secrets and variables are not referenced by repos.toml, they are
+// reported but never deleted.
+type SecretSyncResult struct {
+	Repo string
+
+	Created []string
+	Updated []string
+	Extra   []string
+}
+
+type actionsVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type actionsVariables struct {
+	TotalCount int               `json:"total_count"`
+	Variables  []actionsVariable `json:"variables"`
+}
+
+// SyncSecrets creates or updates secrets and variables referenced by repos
+// with values, and reports extra ones of every repo. Secrets could not be
+// read back, so they are always updated, while variables are only updated
+// if their values changed.
+//
+// Nothing will be changed in dry run.
+func (g *Github) SyncSecrets(ctx context.Context, repos model.Repos, values model.SecretValues, dryRun bool) ([]SecretSyncResult, error) {
+	rs := make([]SecretSyncResult, 0, len(repos))
+	// Repos without secrets and variables in repos.toml are still listed to
+	// report extra ones.
+	for _, repo := range repos {
+		r := SecretSyncResult{Repo: repo.Name}
+		if err := g.syncRepoSecrets(ctx, repo, values, dryRun, &r); err != nil {
+			return nil, err
+		}
+		if err := g.syncRepoVariables(ctx, repo, values, dryRun, &r); err != nil {
+			return nil, err
+		}
+		sort.Strings(r.Extra)
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func (g *Github) syncRepoSecrets(ctx context.Context, repo model.Repo, values model.SecretValues, dryRun bool, r *SecretSyncResult) error {
+	existing, err := g.listRepoSecrets(ctx, repo.Name)
+	if err != nil {
+		return err
+	}
+
+	expected := make(map[string]struct{}, len(repo.Secrets))
+	for _, name := range repo.Secrets {
+		expected[name] = struct{}{}
+	}
+	for name := range existing {
+		if _, ok := expected[name]; !ok {
+			r.Extra = append(r.Extra, name)
+		}
+	}
+	if len(repo.Secrets) == 0 {
+		return nil
+	}
+
+	var pk *github.PublicKey
+	if !dryRun {
+		pk, _, err = g.client.Actions.GetRepoPublicKey(ctx, g.owner, repo.Name)
+		if err != nil {
+			return fmt.Errorf("github get public key of %s: %w", repo.Name, err)
+		}
+	}
+
+	for _, name := range repo.Secrets {
+		value, ok := values.Secrets[name]
+		if !ok {
+			return fmt.Errorf("value of secret %s is missing", name)
+		}
+		if _, ok := existing[name]; ok {
+			r.Updated = append(r.Updated, name)
+		} else {
+			r.Created = append(r.Created, name)
+		}
+		if dryRun {
+			continue
+		}
+
+		sealed, err := model.SealSecret(value, pk.GetKey())
+		if err != nil {
+			return fmt.Errorf("seal secret %s for %s: %w", name, repo.Name, err)
+		}
+		_, err = g.client.Actions.CreateOrUpdateRepoSecret(ctx, g.owner, repo.Name, &github.EncryptedSecret{
+			Name:           name,
+			KeyID:          pk.GetKeyID(),
+			EncryptedValue: sealed,
+		})
+		if err != nil {
+			return fmt.Errorf("github update secret %s of %s: %w", name, repo.Name, err)
+		}
+		g.logger.Info("synced secret", zap.String("repo", repo.Name), zap.String("secret", name))
+	}
+	return nil
+}
+
+func (g *Github) syncRepoVariables(ctx context.Context, repo model.Repo, values model.SecretValues, dryRun bool, r *SecretSyncResult) error {
+	existing, err := g.listRepoVariables(ctx, repo.Name)
+	if err != nil {
+		return err
+	}
+
+	expected := make(map[string]struct{}, len(repo.Variables))
+	for _, name := range repo.Variables {
+		expected[name] = struct{}{}
+	}
+	for name := range existing {
+		if _, ok := expected[name]; !ok {
+			r.Extra = append(r.Extra, name)
+		}
+	}
+
+	for _, name := range repo.Variables {
+		value, ok := values.Variables[name]
+		if !ok {
+			return fmt.Errorf("value of variable %s is missing", name)
+		}
+
+		method, path := "POST", fmt.Sprintf("repos/%s/%s/actions/variables", g.owner, repo.Name)
+		if old, ok := existing[name]; ok {
+			if old == value {
+				continue
+			}
+			method, path = "PATCH", fmt.Sprintf("%s/%s", path, name)
+			r.Updated = append(r.Updated, name)
+		} else {
+			r.Created = append(r.Created, name)
+		}
+		if dryRun {
+			continue
+		}
+
+		_, err = g.rawRequest(ctx, method, path, actionsVariable{Name: name, Value: value}, nil)
+		if err != nil {
+			return fmt.Errorf("github update variable %s of %s: %w", name, repo.Name, err)
+		}
+		g.logger.Info("synced variable", zap.String("repo", repo.Name), zap.String("variable", name))
+	}
+	return nil
+}
+
+func (g *Github) listRepoSecrets(ctx context.Context, repo string) (map[string]struct{}, error) {
+	secrets := make(map[string]struct{})
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		ss, resp, err := g.client.Actions.ListRepoSecrets(ctx, g.owner, repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list secrets of %s: %w", repo, err)
+		}
+		for _, v := range ss.Secrets {
+			secrets[v.Name] = struct{}{}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return secrets, nil
+}
+
+// listRepoVariables returns values of actions variables, go-github doesn't
+// support variables yet so they are requested directly.
+func (g *Github) listRepoVariables(ctx context.Context, repo string) (map[string]string, error) {
+	variables := make(map[string]string)
+	page := 1
+	for {
+		var vs actionsVariables
+		resp, err := g.rawRequest(ctx, "GET",
+			fmt.Sprintf("repos/%s/%s/actions/variables?per_page=30&page=%d", g.owner, repo, page), nil, &vs)
+		if err != nil {
+			return nil, fmt.Errorf("github list variables of %s: %w", repo, err)
+		}
+		for _, v := range vs.Variables {
+			variables[v.Name] = v.Value
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+	return variables, nil
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// securityPolicyPaths are paths github looks up SECURITY.md in.
+var securityPolicyPaths = []string{
+	"SECURITY.md",
+	".github/SECURITY.md",
+	"docs/SECURITY.md",
+}
+
+type securityStatus struct {
+	Status string `json:"status"`
+}
+
+// securityRepo is the part of repo which is not supported by go-github yet.
+type securityRepo struct {
+	DefaultBranch       string `json:"default_branch"`
+	Private             bool   `json:"private"`
+	SecurityAndAnalysis *struct {
+		SecretScanning            *securityStatus `json:"secret_scanning"`
+		DependabotSecurityUpdates *securityStatus `json:"dependabot_security_updates"`
+	} `json:"security_and_analysis"`
+}
+
+type workflowPermissions struct {
+	DefaultWorkflowPermissions string `json:"default_workflow_permissions"`
+}
+
+type forkPRApproval struct {
+	ApprovalPolicy string `json:"approval_policy"`
+}
+
+// AuditSecurity checks the security posture of repos.
+func (g *Github) AuditSecurity(ctx context.Context, repos []string) (rs []model.SecurityReport, err error) {
+	// SECURITY.md in the .github repo is the default of all repos.
+	orgPolicy := false
+	for _, path := range securityPolicyPaths {
+		content, _, err := g.GetFile(ctx, ".github", path)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			orgPolicy = true
+			break
+		}
+	}
+
+	for _, repo := range repos {
+		r, err := g.auditRepoSecurity(ctx, repo, orgPolicy)
+		if err != nil {
+			g.logger.Error("audit security", zap.String("repo", repo), zap.Error(err))
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func (g *Github) auditRepoSecurity(ctx context.Context, repo string, orgPolicy bool) (r model.SecurityReport, err error) {
+	checks := make([]model.SecurityCheck, 0, len(model.SecurityChecks))
+
+	var sr securityRepo
+	_, err = g.rawRequest(ctx, "GET", fmt.Sprintf("repos/%s/%s", g.owner, repo), nil, &sr)
+	if err != nil {
+		return r, err
+	}
+
+	sa := sr.SecurityAndAnalysis
+	if sa == nil || sa.SecretScanning == nil {
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckSecretScanning, "not available"))
+	} else {
+		c := model.NewSecurityCheck(model.CheckSecretScanning, sa.SecretScanning.Status == "enabled", "")
+		c.Fixable = true
+		checks = append(checks, c)
+	}
+	if sa == nil || sa.DependabotSecurityUpdates == nil {
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckDependabotUpdates, "not available"))
+	} else {
+		c := model.NewSecurityCheck(model.CheckDependabotUpdates, sa.DependabotSecurityUpdates.Status == "enabled", "")
+		c.Fixable = true
+		checks = append(checks, c)
+	}
+
+	// Checks not visible to the token are skipped, like branch protection of
+	// private repos on free plans or actions settings for non admins.
+	enabled, _, err := g.client.Repositories.GetVulnerabilityAlerts(ctx, g.owner, repo)
+	switch {
+	case err == nil:
+		c := model.NewSecurityCheck(model.CheckVulnerabilityAlerts, enabled, "")
+		c.Fixable = true
+		checks = append(checks, c)
+	case isForbidden(err):
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckVulnerabilityAlerts, "forbidden"))
+	default:
+		return r, fmt.Errorf("get vulnerability alerts: %w", err)
+	}
+
+	_, _, err = g.client.Repositories.GetBranchProtection(ctx, g.owner, repo, sr.DefaultBranch)
+	switch {
+	case err == nil:
+		checks = append(checks, model.NewSecurityCheck(model.CheckBranchProtection, true, ""))
+	case isNotFound(err):
+		checks = append(checks, model.NewSecurityCheck(model.CheckBranchProtection, false,
+			fmt.Sprintf("branch %s is not protected", sr.DefaultBranch)))
+	case isForbidden(err):
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckBranchProtection, "forbidden"))
+	default:
+		return r, fmt.Errorf("get branch protection: %w", err)
+	}
+
+	var wp workflowPermissions
+	_, err = g.rawRequest(ctx, "GET", fmt.Sprintf("repos/%s/%s/actions/permissions/workflow", g.owner, repo), nil, &wp)
+	switch {
+	case err == nil:
+		c := model.NewSecurityCheck(model.CheckWorkflowPermissions, wp.DefaultWorkflowPermissions == "read",
+			fmt.Sprintf("default permission is %s", wp.DefaultWorkflowPermissions))
+		c.Fixable = true
+		checks = append(checks, c)
+	case isNotFound(err):
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckWorkflowPermissions, "actions disabled"))
+	case isForbidden(err):
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckWorkflowPermissions, "forbidden"))
+	default:
+		return r, fmt.Errorf("get workflow permissions: %w", err)
+	}
+
+	if sr.Private {
+		// Private repos don't run workflows from outside contributors.
+		checks = append(checks, model.SkippedSecurityCheck(model.CheckForkPRApproval, "private repo"))
+	} else {
+		var fa forkPRApproval
+		_, err = g.rawRequest(ctx, "GET", fmt.Sprintf("repos/%s/%s/actions/permissions/fork-pr-contributor-approval", g.owner, repo), nil, &fa)
+		switch {
+		case err == nil:
+			passed := fa.ApprovalPolicy == "first_time_contributors" || fa.ApprovalPolicy == "all_external_contributors"
+			c := model.NewSecurityCheck(model.CheckForkPRApproval, passed,
+				fmt.Sprintf("approval policy is %s", fa.ApprovalPolicy))
+			c.Fixable = true
+			checks = append(checks, c)
+		case isNotFound(err):
+			checks = append(checks, model.SkippedSecurityCheck(model.CheckForkPRApproval, "not available"))
+		case isForbidden(err):
+			checks = append(checks, model.SkippedSecurityCheck(model.CheckForkPRApproval, "forbidden"))
+		default:
+			return r, fmt.Errorf("get fork pr approval policy: %w", err)
+		}
+	}
+
+	policy := orgPolicy
+	for _, path := range securityPolicyPaths {
+		if policy {
+			break
+		}
+		content, _, err := g.GetFile(ctx, repo, path)
+		if err != nil {
+			return r, err
+		}
+		policy = content != nil
+	}
+	checks = append(checks, model.NewSecurityCheck(model.CheckSecurityPolicy, policy, ""))
+
+	return model.NewSecurityReport(repo, checks), nil
+}
+
+// FixSecurity changes settings of failed checks which are fixable, and
+// returns names of fixed checks.
+func (g *Github) FixSecurity(ctx context.Context, r model.SecurityReport) (fixed []string, err error) {
+	for _, c := range r.Checks {
+		if c.Passed || c.Skipped || !c.Fixable {
+			continue
+		}
+
+		switch c.Name {
+		case model.CheckSecretScanning:
+			body := map[string]interface{}{
+				"security_and_analysis": map[string]interface{}{
+					"secret_scanning": securityStatus{Status: "enabled"},
+				},
+			}
+			_, err = g.rawRequest(ctx, "PATCH", fmt.Sprintf("repos/%s/%s", g.owner, r.Repo), body, nil)
+		case model.CheckDependabotUpdates:
+			_, err = g.client.Repositories.EnableAutomatedSecurityFixes(ctx, g.owner, r.Repo)
+		case model.CheckVulnerabilityAlerts:
+			_, err = g.client.Repositories.EnableVulnerabilityAlerts(ctx, g.owner, r.Repo)
+		case model.CheckWorkflowPermissions:
+			_, err = g.rawRequest(ctx, "PUT", fmt.Sprintf("repos/%s/%s/actions/permissions/workflow", g.owner, r.Repo),
+				workflowPermissions{DefaultWorkflowPermissions: "read"}, nil)
+		case model.CheckForkPRApproval:
+			_, err = g.rawRequest(ctx, "PUT", fmt.Sprintf("repos/%s/%s/actions/permissions/fork-pr-contributor-approval", g.owner, r.Repo),
+				forkPRApproval{ApprovalPolicy: "first_time_contributors"}, nil)
+		default:
+			continue
+		}
+		if err != nil {
+			return fixed, fmt.Errorf("fix %s of %s: %w", c.Name, r.Repo, err)
+		}
+		g.logger.Info("Fixed security check",
+			zap.String("repo", r.Repo),
+			zap.String("check", c.Name))
+		fixed = append(fixed, c.Name)
+	}
+	return fixed, nil
+}
+
+// rawRequest sends a request for APIs which are not supported by go-github
+// yet, the response will be decoded into v if it's not nil.
+func (g *Github) rawRequest(ctx context.Context, method, path string, body, v interface{}) (*github.Response, error) {
+	req, err := g.client.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return g.client.Do(ctx, req, v)
+}
+
+func isNotFound(err error) bool {
+	var e *github.ErrorResponse
+	return errors.As(err, &e) && e.Response.StatusCode == 404
+}
+
+func isForbidden(err error) bool {
+	var e *github.ErrorResponse
+	return errors.As(err, &e) && e.Response.StatusCode == 403
+}
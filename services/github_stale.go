@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// StaleTriage is an action taken on a stale issue or pull request.
+type StaleTriage struct {
+	Repo        string
+	Number      int
+	Title       string
+	URL         string
+	PullRequest bool
+	// Action is one of model.StaleMark, model.StaleUnmark and
+	// model.StaleClose.
+	Action string
+}
+
+// TriageStale marks, unmarks and closes stale issues and pull requests of
+// the repo by the policy, see model.StalePolicy.Decide.
+//
+// Actions are returned without being taken in dry run.
+func (g *Github) TriageStale(ctx context.Context, repo string, policy model.StalePolicy, now time.Time, dryRun bool) (ts []StaleTriage, err error) {
+	if policy.IsDisabled() {
+		return nil, nil
+	}
+
+	issues, err := g.listOpenIssues(ctx, repo, "updated")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		// Locked items can't be commented by us.
+		if issue.GetLocked() {
+			continue
+		}
+
+		item := model.StaleItem{
+			Number:      issue.GetNumber(),
+			PullRequest: issue.IsPullRequest(),
+			UpdatedAt:   issue.GetUpdatedAt(),
+		}
+		for _, v := range issue.Labels {
+			item.Labels = append(item.Labels, v.GetName())
+			if v.GetName() == policy.GetLabel() {
+				item.MarkedAt, err = g.labeledAt(ctx, repo, issue.GetNumber(), policy.GetLabel())
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		action := policy.Decide(item, now)
+		if action == "" {
+			continue
+		}
+		ts = append(ts, StaleTriage{
+			Repo:        repo,
+			Number:      item.Number,
+			Title:       issue.GetTitle(),
+			URL:         issue.GetHTMLURL(),
+			PullRequest: item.PullRequest,
+			Action:      action,
+		})
+		if dryRun {
+			continue
+		}
+
+		err = g.triageStaleItem(ctx, repo, item, policy, action)
+		if err != nil {
+			return nil, err
+		}
+		g.logger.Info("triage stale",
+			zap.String("repo", repo),
+			zap.Int("number", item.Number),
+			zap.String("action", action))
+	}
+	return ts, nil
+}
+
+func (g *Github) triageStaleItem(ctx context.Context, repo string, item model.StaleItem, policy model.StalePolicy, action string) (err error) {
+	switch action {
+	case model.StaleMark:
+		_, _, err = g.client.Issues.AddLabelsToIssue(ctx, g.owner, repo, item.Number, []string{policy.GetLabel()})
+		if err != nil {
+			return fmt.Errorf("github add label to %s#%d: %w", repo, item.Number, err)
+		}
+		_, err = g.CreateComment(ctx, repo, item.Number, policy.GetComment(item.PullRequest))
+		if err != nil {
+			return fmt.Errorf("github comment on %s#%d: %w", repo, item.Number, err)
+		}
+	case model.StaleUnmark:
+		_, err = g.client.Issues.RemoveLabelForIssue(ctx, g.owner, repo, item.Number, policy.GetLabel())
+		if err != nil {
+			return fmt.Errorf("github remove label from %s#%d: %w", repo, item.Number, err)
+		}
+	case model.StaleClose:
+		_, err = g.CreateComment(ctx, repo, item.Number, policy.GetCloseComment(item.PullRequest))
+		if err != nil {
+			return fmt.Errorf("github comment on %s#%d: %w", repo, item.Number, err)
+		}
+		_, _, err = g.client.Issues.Edit(ctx, g.owner, repo, item.Number, &github.IssueRequest{
+			State: github.String("closed"),
+		})
+		if err != nil {
+			return fmt.Errorf("github close %s#%d: %w", repo, item.Number, err)
+		}
+	}
+	return nil
+}
+
+// labeledAt returns when the label was added to the issue most recently.
+func (g *Github) labeledAt(ctx context.Context, repo string, number int, label string) (at time.Time, err error) {
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+	for {
+		es, resp, err := g.client.Issues.ListIssueEvents(ctx, g.owner, repo, number, opt)
+		if err != nil {
+			return at, fmt.Errorf("github list events of %s#%d: %w", repo, number, err)
+		}
+		for _, e := range es {
+			if e.GetEvent() == "labeled" && e.GetLabel().GetName() == label && e.GetCreatedAt().After(at) {
+				at = e.GetCreatedAt()
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return at, nil
+}
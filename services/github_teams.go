@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v35/github"
+	"go.uber.org/zap"
+
+	"github.com/beyondstorage/go-community/model"
+)
+
+// UnmanagedTeam is a team which exists on github but not in teams.toml.
+type UnmanagedTeam struct {
+	Slug    string
+	Repos   []string
+	Members int
+	// Allowed means the team is kept by the allow list.
+	Allowed bool
+	// Children are child teams of the team on github.
+	Children []string
+	// Inherited are repos inherited from parent teams, they are listed in
+	// Repos but can only be removed from the parent.
+	Inherited []string
+}
+
+// UnmanagedTeams lists all teams which exist on github but not in teams.
+func (g *Github) UnmanagedTeams(ctx context.Context, teams model.Teams, policy model.UnmanagedTeamPolicy) (ts []UnmanagedTeam, err error) {
+	gts, err := g.listTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]string)
+	for slug, v := range gts {
+		if p := v.GetParent().GetSlug(); p != "" {
+			children[p] = append(children[p], slug)
+		}
+	}
+
+	// Repos of teams are cached, as parents are shared by children.
+	teamRepos := make(map[string][]string)
+	listRepos := func(slug string) ([]string, error) {
+		if repos, ok := teamRepos[slug]; ok {
+			return repos, nil
+		}
+		repos, err := g.listTeamRepos(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		teamRepos[slug] = repos
+		return repos, nil
+	}
+
+	for slug := range gts {
+		if _, ok := teams[slug]; ok {
+			continue
+		}
+
+		repos, err := listRepos(slug)
+		if err != nil {
+			return nil, err
+		}
+		// Repos of the parent include repos of all its ancestors.
+		var inherited []string
+		if p := gts[slug].GetParent().GetSlug(); p != "" {
+			inherited, err = listRepos(p)
+			if err != nil {
+				return nil, err
+			}
+		}
+		members, err := g.listTeamMembers(ctx, slug, "all")
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(children[slug])
+		ts = append(ts, UnmanagedTeam{
+			Slug:      slug,
+			Repos:     repos,
+			Members:   len(members),
+			Allowed:   policy.IsAllowed(slug),
+			Children:  children[slug],
+			Inherited: inherited,
+		})
+	}
+
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Slug < ts[j].Slug
+	})
+	return ts, nil
+}
+
+// PruneTeam removes all repo access of the unmanaged team, or deletes it in
+// delete mode. Teams on the allow list are never touched.
+//
+// Repos inherited from parent teams are kept in revoke mode, and teams with
+// child teams are never deleted, as deleting a team deletes its children.
+func (g *Github) PruneTeam(ctx context.Context, team UnmanagedTeam, mode string) (err error) {
+	if team.Allowed {
+		return nil
+	}
+
+	switch mode {
+	case model.UnmanagedReport:
+		return nil
+	case model.UnmanagedRevoke:
+		inherited := make(map[string]struct{}, len(team.Inherited))
+		for _, repo := range team.Inherited {
+			inherited[repo] = struct{}{}
+		}
+		for _, repo := range team.Repos {
+			if _, ok := inherited[repo]; ok {
+				continue
+			}
+			_, err = g.client.Teams.RemoveTeamRepoBySlug(ctx, g.owner, team.Slug, g.owner, repo)
+			if err != nil {
+				return fmt.Errorf("remove team repo by slug: %w", err)
+			}
+			g.logger.Info("Removed repo from unmanaged team",
+				zap.String("team", team.Slug),
+				zap.String("repo", repo))
+		}
+		return nil
+	case model.UnmanagedDelete:
+		if len(team.Children) > 0 {
+			g.logger.Warn("skip deleting unmanaged team with child teams",
+				zap.String("team", team.Slug),
+				zap.Strings("children", team.Children))
+			return nil
+		}
+		_, err = g.client.Teams.DeleteTeamBySlug(ctx, g.owner, team.Slug)
+		if err != nil {
+			return fmt.Errorf("delete team by slug: %w", err)
+		}
+		g.logger.Info("Deleted unmanaged team", zap.String("team", team.Slug))
+		return nil
+	default:
+		return fmt.Errorf("invalid mode %q", mode)
+	}
+}
+
+func (g *Github) listTeamRepos(ctx context.Context, team string) (repos []string, err error) {
+	opt := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	for {
+		rps, resp, err := g.client.Teams.ListTeamReposBySlug(ctx, g.owner, team, opt)
+		if err != nil {
+			return nil, fmt.Errorf("github list team repos: %w", err)
+		}
+		for _, v := range rps {
+			repos = append(repos, v.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
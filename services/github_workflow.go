@@ -0,0 +1,55 @@
+package services
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// workflowExts are extensions of github workflow files.
+var workflowExts = []string{".yml", ".yaml"}
+
+func isWorkflowFile(filename string) bool {
+	for _, ext := range workflowExts {
+		if strings.HasSuffix(filename, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// workflowName returns the workflow name without extension.
+func workflowName(filename string) string {
+	for _, ext := range workflowExts {
+		if strings.HasSuffix(filename, ext) {
+			return strings.TrimSuffix(filename, ext)
+		}
+	}
+	return filename
+}
+
+// listLocalActions returns file names of local actions keyed by workflow
+// name.
+func listLocalActions(actionPath string) (map[string]string, error) {
+	fis, err := ioutil.ReadDir(actionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for _, fi := range fis {
+		if fi.IsDir() || !isWorkflowFile(fi.Name()) {
+			continue
+		}
+		files[workflowName(fi.Name())] = fi.Name()
+	}
+	return files, nil
+}
+
+// localActionFile returns the file name of the local action, the name with
+// .yml is returned if it doesn't exist, so that reading it reports the error.
+func localActionFile(files map[string]string, name string) string {
+	if v, ok := files[name]; ok {
+		return v
+	}
+	return name + ".yml"
+}
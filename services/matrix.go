@@ -95,3 +95,39 @@ func (m *Matrix) PublicRoom(roomid string) (err error) {
 	}
 	return nil
 }
+
+// SendDirectMessage creates a direct chat with the user and sends content.
+func (m *Matrix) SendDirectMessage(userID, content string) (err error) {
+	resp, err := m.client.CreateRoom(&mautrix.ReqCreateRoom{
+		Preset:   "trusted_private_chat",
+		Invite:   []id.UserID{id.UserID(userID)},
+		IsDirect: true,
+	})
+	if err != nil {
+		return
+	}
+
+	_, err = m.client.SendText(resp.RoomID, content)
+	return
+}
+
+// JoinedMembers returns all user ids joined the room.
+func (m *Matrix) JoinedMembers(roomid string) (members map[string]struct{}, err error) {
+	resp, err := m.client.JoinedMembers(id.RoomID(roomid))
+	if err != nil {
+		return
+	}
+
+	members = make(map[string]struct{}, len(resp.Joined))
+	for v := range resp.Joined {
+		members[v.String()] = struct{}{}
+	}
+	return members, nil
+}
+
+func (m *Matrix) Invite(roomid, userID string) (err error) {
+	_, err = m.client.InviteUser(id.RoomID(roomid), &mautrix.ReqInviteUser{
+		UserID: id.UserID(userID),
+	})
+	return
+}